@@ -1,8 +1,38 @@
 package main
 
-import "dataserver/internal/app/dataserver"
+import (
+	"dataserver/internal/app/dataserver"
+	"flag"
+	log "github.com/sirupsen/logrus"
+	"strconv"
+)
 
 func main() {
+	configPath := flag.String("config", "configs/config.yml", "path to the configuration file, or empty to use only the defaults and environment")
+	flag.Parse()
+	args := flag.Args()
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "purge":
+			if len(args) < 2 {
+				log.Fatal("Usage: dataserver purge <cid>")
+			}
+			cid, err := strconv.Atoi(args[1])
+			if err != nil {
+				log.Fatal("CID must be a number.")
+			}
+			dataserver.Purge(*configPath, cid)
+			return
+		case "logbook":
+			dataserver.QueryLogbook(*configPath, args[1:])
+			return
+		case "validate-config":
+			dataserver.ValidateConfig(*configPath)
+			return
+		}
+	}
+
 	// Start er up!
-	dataserver.Start()
+	dataserver.Start(*configPath)
 }
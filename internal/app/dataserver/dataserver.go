@@ -1,56 +1,240 @@
 package dataserver
 
 import (
+	"context"
+	"dataserver/internal/pkg/api"
+	"dataserver/internal/pkg/clock"
 	"dataserver/internal/pkg/config"
 	"dataserver/internal/pkg/dataserver"
 	"dataserver/internal/pkg/fsd"
+	"dataserver/internal/pkg/geo"
+	"dataserver/internal/pkg/logbook"
+	"dataserver/internal/pkg/rpc"
+	"dataserver/internal/pkg/sbs"
 	"fmt"
-	"github.com/minio/minio-go"
-	"github.com/pkg/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
+	"net"
 	"net/http"
+	"net/textproto"
+	"os"
 	"sync"
-	"time"
 )
 
-// Start connects and begins parsing and saving data files.
-func Start() {
+// Start connects and begins parsing and saving data files until SIGTERM or an interrupt,
+// then leaves the FSD network and flushes every output.
+func Start(configPath string) {
+	ctx, cancel := context.WithCancel(context.Background())
+	go cancelOnSignal(cancel)
+
 	// Configuration
-	config.ReadConfig()
-	config.ConfigureSentry()
-	producer := config.ConfigureKafka()
-	defer producer.Close()
+	cfg := loadConfig(configPath)
+	log.SetLevel(cfg.Log.Level)
+	if cfg.Sentry.Enabled {
+		config.ConfigureSentry(cfg.Sentry)
+	} else {
+		log.Debug("Sentry disabled.")
+	}
+	producer := configureKafka(cfg.Kafka)
+	sessionLog := openLogbook(cfg.Logbook)
+	defer sessionLog.Close()
 
 	// Connect to FSD
-	conn := fsd.Connect()
-	defer func() {
-		if err := conn.Close(); err != nil {
-			log.Fatal("Failed to close FSD connection.")
-		}
-	}()
+	conn := fsd.Connect(cfg.FSD.IP, cfg.FSD.Port)
+	writer := fsd.NewWriter(conn, fsd.WriterOptions{
+		Queue:   cfg.FSD.Writer.Queue,
+		Timeout: cfg.FSD.Writer.Timeout,
+		Rate:    cfg.FSD.Writer.Rate,
+		Burst:   cfg.FSD.Writer.Burst,
+	})
 
 	// Create our application context
-	context := dataserver.Context{
-		Consumer: conn,
+	ds := &dataserver.Context{
+		Config:   cfg,
+		Consumer: textproto.NewConn(conn),
+		Writer:   writer,
 		Producer: producer,
 		ClientList: &dataserver.ClientList{
 			Mutex: &sync.RWMutex{},
 		},
+		OptOut:       dataserver.NewOptOutList(cfg.Data.Privacy.OptOut),
+		KafkaTypes:   dataserver.ClientTypesOf(cfg.Kafka.Types),
+		Logbook:      sessionLog,
+		Events:       dataserver.NewEventBus(cfg.API.StreamHistory),
+		Index:        geo.NewIndex(cfg.API.GeoCellSize),
+		Mutex:        &sync.RWMutex{},
+		Clock:        clock.Real{},
+		Presences:    cfg.FSD.Presences,
+		Deduplicator: dataserver.NewDeduplicator(cfg.FSD.DuplicateWindow),
 	}
 
-	// Set ourselves up as an FSD server
-	go context.SetupServer()
-
-	// Add a fake client to request data with
-	go context.AddFSDClient()
-
 	// Begin listening for updates
-	go update()
+	publisher := newPublisher(cfg, ds.Clock)
+	publishing, stopPublishing := context.WithCancel(context.Background())
+	go publisher.run(publishing, ds.OptOut)
+
+	// Reload the configuration on SIGHUP or from the admin endpoint
+	reload := &reloader{
+		path:      configPath,
+		context:   ds,
+		publisher: publisher,
+	}
+	go exposeAdmin(reload)
 	go exposeMetrics()
-	go context.RequestATIS()
-	go context.RemoveTimedOutClients()
-	context.Listen()
+	go exposeAPI(ds)
+	go exposeRPC(ds)
+	go exposeSBS(ds)
+
+	// Set ourselves up as an FSD server, add a fake client to request data with, and keep everything up to date
+	loops := &sync.WaitGroup{}
+	for _, loop := range []func(context.Context){
+		ds.SetupServer,
+		ds.AddFSDClient,
+		ds.RequestATIS,
+		ds.RemoveTimedOutClients,
+		ds.ReloadOptOutList,
+		reload.watchSignals,
+	} {
+		loops.Add(1)
+		go func(loop func(context.Context)) {
+			defer loops.Done()
+			loop(ctx)
+		}(loop)
+	}
+
+	listened := make(chan error, 1)
+	go func() {
+		listened <- ds.Listen(ctx)
+	}()
+	select {
+	case err := <-listened:
+		log.WithField("error", err).Fatal("Lost FSD connection.")
+	case <-ctx.Done():
+	}
+
+	// Shut down in order, giving up on any step which takes us past the deadline
+	log.Info("Shutting down.")
+	deadline, cancelDeadline := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancelDeadline()
+	within(deadline, "stop loops", loops.Wait)
+	within(deadline, "remove client", func() {
+		ds.SendRemoveClient()
+		writer.Close()
+	})
+	within(deadline, "close FSD connection", func() {
+		if err := conn.Close(); err != nil {
+			log.WithField("error", err).Error("Failed to close FSD connection.")
+		}
+		<-listened
+	})
+	within(deadline, "write final data file and finish uploads", func() {
+		stopPublishing()
+		<-publisher.done
+	})
+	within(deadline, "flush Kafka", func() {
+		flushKafka(deadline, ds.ReplaceProducer(nil))
+	})
+	log.Info("Shut down.")
+}
+
+// Purge erases a member's data from the stored data files, their copies in every bucket and the logbook.
+func Purge(configPath string, cid int) {
+	cfg := loadConfig(configPath)
+	err := dataserver.PurgeMember(dataserver.NewFileSink(cfg.Data.File), cid)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"cid":   cid,
+			"error": err,
+		}).Fatal("Failed to purge member data.")
+	}
+	for _, bucket := range cfg.S3 {
+		err = purgeBucket(bucket, cid)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"cid":    cid,
+				"bucket": bucket.Name,
+				"error":  err,
+			}).Fatal("Failed to purge member data from S3.")
+		}
+	}
+	sessionLog := openLogbook(cfg.Logbook)
+	defer sessionLog.Close()
+	count, err := sessionLog.Purge(cid)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"cid":   cid,
+			"error": err,
+		}).Fatal("Failed to purge member sessions from logbook.")
+	}
+	log.WithFields(log.Fields{
+		"cid":      cid,
+		"sessions": count,
+	}).Info("Member data purged.")
+}
+
+// ValidateConfig checks the configuration, printing every problem found.
+func ValidateConfig(configPath string) {
+	_, errs := readConfig(configPath)
+	if len(errs) > 0 {
+		for _, v := range errs {
+			fmt.Fprintln(os.Stderr, v)
+		}
+		os.Exit(1)
+	}
+	fmt.Println("Configuration is valid.")
+}
+
+// registerFormats adds the formats encoded outside the dataserver package before the first
+// configuration is validated, so reloads never change the formats while they are written
+var registerFormats sync.Once
+
+// readConfig loads the configuration and collects every problem with it
+func readConfig(configPath string) (*config.Config, config.Errors) {
+	registerFormats.Do(func() {
+		dataserver.RegisterFormat(dataserver.FormatProtobuf, rpc.ProtobufFileName, rpc.EncodeProtobuf)
+	})
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		errs, ok := err.(config.Errors)
+		if !ok {
+			return nil, config.Errors{err.Error()}
+		}
+		return cfg, append(errs, dataserver.ValidateConfig(cfg)...)
+	}
+	return cfg, dataserver.ValidateConfig(cfg)
+}
+
+// loadConfig loads the configuration, exiting if there is any problem with it
+func loadConfig(configPath string) *config.Config {
+	cfg, errs := readConfig(configPath)
+	if len(errs) > 0 {
+		log.WithField("errors", []string(errs)).Fatal("Invalid configuration.")
+	}
+	return cfg
+}
+
+// configureKafka connects the Kafka producer if Kafka is enabled
+func configureKafka(cfg config.Kafka) *kafka.Producer {
+	if !cfg.Enabled {
+		log.Debug("Kafka disabled.")
+		return nil
+	}
+	return config.ConfigureKafka(cfg)
+}
+
+// openLogbook opens the session logbook if one is configured
+func openLogbook(cfg config.Logbook) *logbook.Logbook {
+	if cfg.Path == "" {
+		log.Debug("Logbook not defined.")
+		return nil
+	}
+	sessionLog, err := logbook.Open(cfg.Path)
+	if err != nil {
+		log.WithField("error", err).Fatal("Failed to open logbook.")
+	}
+	return sessionLog
 }
 
 // exposeMetrics listens for Prometheus scrapes
@@ -62,68 +246,62 @@ func exposeMetrics() {
 	}
 }
 
-// s3Loop pushes the data files to S3
-func s3Loop(k string) {
-	endPoint, _ := config.Cfg.String(fmt.Sprintf("s3.%s.endpoint", k))
-	accessKeyID, _ := config.Cfg.String(fmt.Sprintf("s3.%s.accessKeyID", k))
-	secretAccessKey, _ := config.Cfg.String(fmt.Sprintf("s3.%s.secretAccessKey", k))
-	bucketName, _ := config.Cfg.String(fmt.Sprintf("s3.%s.bucketName", k))
-	contentType := "application/json"
-	objectName := "vatsim-data.json"
-	userMetaData := map[string]string{"x-amz-acl": "public-read"}
-
-	minioClient, err := minio.New(endPoint, accessKeyID, secretAccessKey, true)
-	if err != nil {
-		log.WithField("error", err).Error("Failed to create new S3 client.")
+// exposeAPI serves the client list API next to the metrics or on its own port
+func exposeAPI(context *dataserver.Context) {
+	server := api.API{
+		Context:     context,
+		ClientTypes: dataserver.ClientTypesOf(context.Configuration().API.Types),
 	}
-
-	n, err := minioClient.FPutObject(bucketName, objectName, "directoryvatsim-data.json", minio.PutObjectOptions{ContentType: contentType, UserMetadata: userMetaData})
+	port := context.Configuration().API.Port
+	if port == "" {
+		server.Register(http.DefaultServeMux)
+		return
+	}
+	mux := http.NewServeMux()
+	server.Register(mux)
+	err := http.ListenAndServe(":"+port, mux)
 	if err != nil {
-		log.WithField("error", err).Error("Failed to upload object to S3.")
+		log.Fatal("Failed to expose API.")
 	}
-	log.WithFields(log.Fields{
-		"object": objectName,
-		"size":   n,
-	}).Debug("Successfully uploaded object to S3.")
 }
 
-// s3Push sets up  the S3 library and begins the goroutine
-func s3Push() {
-	s3Config, err := config.Cfg.Map("s3")
+// exposeRPC serves the gRPC service if a port is configured
+func exposeRPC(context *dataserver.Context) {
+	port := context.Configuration().RPC.Port
+	if port == "" {
+		log.Debug("gRPC port not defined.")
+		return
+	}
+	listener, err := net.Listen("tcp", ":"+port)
 	if err != nil {
-		log.Fatal("S3 configuration not defined.")
+		log.WithField("error", err).Fatal("Failed to listen for gRPC connections.")
 	}
-	for k := range s3Config {
-		go s3Loop(k)
+	server := grpc.NewServer()
+	service := rpc.Server{
+		Context:     context,
+		ClientTypes: dataserver.ClientTypesOf(context.Configuration().RPC.Types),
 	}
-}
-
-// update handles the creation of a 15 second ticker for updating the data file
-func update() {
-	now := time.Now().UTC()
-	for clientList := range dataserver.Channel {
-		if time.Since(now) >= (15 * time.Second) {
-			err := updateFile(clientList)
-			if err != nil {
-				log.WithField("error", err).Error("Failed to update data file.")
-			}
-			now = time.Now().UTC()
-		}
+	service.Register(server)
+	err = server.Serve(listener)
+	if err != nil {
+		log.WithField("error", err).Fatal("Failed to serve gRPC.")
 	}
 }
 
-// updateFile encodes the current clientList and prints to the data file
-func updateFile(clientList dataserver.ClientList) error {
-	clientJSON, err := dataserver.EncodeJSON(clientList)
+// exposeSBS streams pilot positions as BaseStation messages if a port is configured
+func exposeSBS(context *dataserver.Context) {
+	port := context.Configuration().SBS.Port
+	if port == "" {
+		log.Debug("SBS port not defined.")
+		return
+	}
+	listener, err := net.Listen("tcp", ":"+port)
 	if err != nil {
-		return errors.WithStack(err)
+		log.WithField("error", err).Fatal("Failed to listen for SBS connections.")
 	}
-	err = dataserver.WriteDataFile(clientJSON)
+	server := sbs.Server{Context: context}
+	err = server.Serve(listener)
 	if err != nil {
-		return errors.WithStack(err)
+		log.WithField("error", err).Fatal("Failed to serve SBS.")
 	}
-	log.Debug("Data file updated.")
-	s3Push()
-
-	return nil
 }
@@ -0,0 +1,50 @@
+package dataserver
+
+import (
+	"fmt"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+// QueryLogbook prints a report from the session logbook.
+func QueryLogbook(configPath string, args []string) {
+	cfg := loadConfig(configPath)
+	sessionLog := openLogbook(cfg.Logbook)
+	if sessionLog == nil {
+		log.Fatal("Logbook path not defined.")
+	}
+	defer sessionLog.Close()
+	if len(args) < 2 {
+		log.Fatal("Usage: dataserver logbook hours <cid> | dataserver logbook activity <callsign prefix>")
+	}
+	writer := tabwriter.NewWriter(os.Stdout, 0, 8, 2, ' ', 0)
+	defer writer.Flush()
+	switch args[0] {
+	case "hours":
+		cid, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Fatal("CID must be a number.")
+		}
+		hours, err := sessionLog.HoursByCID(cid)
+		if err != nil {
+			log.WithField("error", err).Fatal("Failed to query logbook.")
+		}
+		fmt.Fprintln(writer, "TYPE\tSESSIONS\tHOURS")
+		for _, v := range hours {
+			fmt.Fprintf(writer, "%s\t%d\t%.1f\n", v.Type, v.Sessions, v.Duration.Hours())
+		}
+	case "activity":
+		activity, err := sessionLog.ControllerActivity(args[1])
+		if err != nil {
+			log.WithField("error", err).Fatal("Failed to query logbook.")
+		}
+		fmt.Fprintln(writer, "CALLSIGN\tSESSIONS\tHOURS\tLAST LOGON")
+		for _, v := range activity {
+			fmt.Fprintf(writer, "%s\t%d\t%.1f\t%s\n", v.Callsign, v.Sessions, v.Duration.Hours(), v.LastLogon.Format("2006-01-02 15:04"))
+		}
+	default:
+		log.Fatalf("Unknown logbook query %s.", args[0])
+	}
+}
@@ -0,0 +1,468 @@
+package dataserver
+
+import (
+	"bytes"
+	"context"
+	"dataserver/internal/pkg/clock"
+	"dataserver/internal/pkg/config"
+	"dataserver/internal/pkg/dataserver"
+	"github.com/minio/minio-go"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"path/filepath"
+	"sync"
+)
+
+// publisher writes the data files and uploads them to every bucket
+type publisher struct {
+	cfg         *config.Config
+	clock       clock.Clock
+	sink        dataserver.FileSink
+	clientTypes dataserver.ClientTypes
+	formats     []dataserver.Format
+	tracks      *dataserver.Tracks
+	deltas      *dataserver.DeltaWriter
+	configs     chan *config.Config
+	uploads     sync.WaitGroup
+	s3Mutex     sync.Mutex
+	s3Running   bool
+	s3Pending   *s3Batch
+	done        chan struct{}
+}
+
+// newPublisher creates the publisher for the configured data files
+func newPublisher(cfg *config.Config, clock clock.Clock) *publisher {
+	sink := dataserver.NewFileSink(cfg.Data.File)
+	return &publisher{
+		cfg:         cfg,
+		clock:       clock,
+		sink:        sink,
+		clientTypes: dataserver.ClientTypesOf(cfg.Data.File.Types),
+		formats:     dataserver.FormatsOf(cfg.Data.File.Formats),
+		tracks:      dataserver.NewTracks(cfg.Data.KML.Tracks),
+		deltas:      dataserver.NewDeltaWriter(sink, cfg.Data.Delta.History),
+		configs:     make(chan *config.Config),
+		done:        make(chan struct{}),
+	}
+}
+
+// run writes the data files at most once every publish interval as the client list changes.
+// When the context is cancelled it writes the latest client list one last time and returns
+// once every upload has finished.
+func (p *publisher) run(ctx context.Context, optOut *dataserver.OptOutList) {
+	defer close(p.done)
+	p.writeNetworkLink()
+	now := p.clock.Now()
+	var latest *dataserver.ClientList
+	for {
+		select {
+		case <-ctx.Done():
+			if latest != nil {
+				p.publish(*latest, optOut)
+				log.Info("Final data file written.")
+			}
+			p.uploads.Wait()
+			return
+		case cfg := <-p.configs:
+			p.apply(cfg)
+		case clientList := <-dataserver.Channel:
+			latest = &clientList
+			if p.clock.Now().Sub(now) >= p.cfg.Timers.Publish {
+				p.publish(clientList, optOut)
+				latest = nil
+				now = p.clock.Now()
+			}
+		}
+	}
+}
+
+// publish writes the client types and members the data files expose
+func (p *publisher) publish(clientList dataserver.ClientList, optOut *dataserver.OptOutList) {
+	clientList = clientList.FilterClientTypes(p.clientTypes)
+	err := p.updateFile(optOut.RedactClientList(clientList, "data_file"))
+	if err != nil {
+		log.WithField("error", err).Error("Failed to update data file.")
+	}
+}
+
+// reconfigure hands a reloaded configuration to the publisher between updates, unless it has stopped
+func (p *publisher) reconfigure(cfg *config.Config) {
+	select {
+	case p.configs <- cfg:
+	case <-p.done:
+	}
+}
+
+// apply switches the data files, formats, tracks, deltas and buckets to a reloaded configuration
+func (p *publisher) apply(cfg *config.Config) {
+	previous := p.cfg
+	p.cfg = cfg
+	p.sink = dataserver.NewFileSink(cfg.Data.File)
+	p.clientTypes = dataserver.ClientTypesOf(cfg.Data.File.Types)
+	p.formats = dataserver.FormatsOf(cfg.Data.File.Formats)
+	if cfg.Data.KML.Tracks != previous.Data.KML.Tracks {
+		p.tracks = dataserver.NewTracks(cfg.Data.KML.Tracks)
+	}
+	p.deltas.Reconfigure(p.sink, cfg.Data.Delta.History)
+	if cfg.Data.KML.Link != previous.Data.KML.Link || cfg.Data.File.Directory != previous.Data.File.Directory || cfg.Timers.Publish != previous.Timers.Publish {
+		p.writeNetworkLink()
+	}
+	log.Debug("Publisher reconfigured.")
+}
+
+// updateFile encodes the current clientList and prints to the data file
+func (p *publisher) updateFile(clientList dataserver.ClientList) error {
+	clientList = p.deltas.Next(clientList)
+	clientJSON, err := dataserver.EncodeJSON(clientList)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = p.sink.WriteDataFile(clientJSON)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	p.tracks.Record(clientList)
+	files := []string{"vatsim-data.json"}
+	for _, format := range p.formats {
+		err = format.Write(p.sink, clientList, p.tracks)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		files = append(files, format.FileName())
+	}
+	deltaFiles, expired, err := p.deltas.Write(clientList)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	log.WithField("serial", clientList.Serial).Debug("Data file updated.")
+	p.s3Push(append(files, deltaFiles...))
+	p.s3Remove(expired)
+
+	return nil
+}
+
+// writeNetworkLink publishes the KML file pointing Google Earth at the KML or KMZ output if a link is configured
+func (p *publisher) writeNetworkLink() {
+	href := p.cfg.Data.KML.Link
+	if href == "" {
+		log.Debug("KML network link not defined.")
+		return
+	}
+	err := p.sink.WriteNetworkLink(href, int(p.cfg.Timers.Publish.Seconds()))
+	if err != nil {
+		log.WithField("error", err).Error("Failed to write KML network link.")
+		return
+	}
+	p.s3Push([]string{dataserver.NetworkLinkFileName})
+}
+
+// s3Object is a file read for upload along with its precompressed variants
+type s3Object struct {
+	name     string
+	data     []byte
+	variants map[dataserver.Encoding][]byte
+}
+
+// s3Batch is the files a publish uploads to and removes from every bucket
+type s3Batch struct {
+	buckets []config.S3
+	objects []s3Object
+	removed []string
+}
+
+// s3Push begins uploading the files to every bucket.
+// The files are read before returning, so the next publish can't change them mid upload.
+func (p *publisher) s3Push(files []string) {
+	if len(p.cfg.S3) == 0 {
+		return
+	}
+	p.queueBatch(s3Batch{buckets: p.cfg.S3, objects: readObjects(p.sink, p.cfg.S3, files)})
+}
+
+// s3Remove begins deleting the files from every bucket
+func (p *publisher) s3Remove(files []string) {
+	if len(files) == 0 || len(p.cfg.S3) == 0 {
+		return
+	}
+	p.queueBatch(s3Batch{buckets: p.cfg.S3, removed: files})
+}
+
+// queueBatch hands a batch to the upload goroutine, starting it if it is idle.
+// Only one batch uploads at a time, and batches queued meanwhile are merged so only the latest copy of a file is uploaded.
+func (p *publisher) queueBatch(batch s3Batch) {
+	p.s3Mutex.Lock()
+	defer p.s3Mutex.Unlock()
+	if p.s3Pending != nil {
+		batch = p.s3Pending.merge(batch)
+		p.s3Pending = &batch
+		return
+	}
+	if p.s3Running {
+		p.s3Pending = &batch
+		return
+	}
+	p.s3Running = true
+	p.uploads.Add(1)
+	go p.uploadBatches(batch)
+}
+
+// uploadBatches uploads a batch and then every batch queued while it did
+func (p *publisher) uploadBatches(batch s3Batch) {
+	defer p.uploads.Done()
+	for {
+		batch.upload()
+		p.s3Mutex.Lock()
+		if p.s3Pending == nil {
+			p.s3Running = false
+			p.s3Mutex.Unlock()
+			return
+		}
+		batch = *p.s3Pending
+		p.s3Pending = nil
+		p.s3Mutex.Unlock()
+	}
+}
+
+// merge combines a batch with a later one, keeping the later copy of each file and the later buckets
+func (b s3Batch) merge(later s3Batch) s3Batch {
+	uploaded := map[string]bool{}
+	for _, v := range later.objects {
+		uploaded[v.name] = true
+	}
+	removed := map[string]bool{}
+	for _, v := range later.removed {
+		removed[v] = true
+	}
+	merged := s3Batch{buckets: later.buckets}
+	for _, v := range b.objects {
+		if !uploaded[v.name] && !removed[v.name] {
+			merged.objects = append(merged.objects, v)
+		}
+	}
+	merged.objects = append(merged.objects, later.objects...)
+	for _, v := range b.removed {
+		if !uploaded[v] && !removed[v] {
+			merged.removed = append(merged.removed, v)
+		}
+	}
+	merged.removed = append(merged.removed, later.removed...)
+	return merged
+}
+
+// upload pushes the batch to every bucket at once and waits for them all
+func (b s3Batch) upload() {
+	var wg sync.WaitGroup
+	for _, v := range b.buckets {
+		wg.Add(1)
+		go func(bucket config.S3) {
+			defer wg.Done()
+			s3Loop(bucket, b.objects)
+			if len(b.removed) > 0 {
+				s3Delete(bucket, b.removed)
+			}
+		}(v)
+	}
+	wg.Wait()
+}
+
+// readObjects reads the files along with the precompressed variants the buckets are configured for,
+// reusing the variants already written to the data directory
+func readObjects(sink dataserver.FileSink, buckets []config.S3, files []string) []s3Object {
+	var encodings []dataserver.Encoding
+	seen := map[dataserver.Encoding]bool{}
+	for _, bucket := range buckets {
+		for _, encoding := range dataserver.EncodingsOf(bucket.Compression) {
+			if !seen[encoding] {
+				seen[encoding] = true
+				encodings = append(encodings, encoding)
+			}
+		}
+	}
+	var objects []s3Object
+	for _, name := range files {
+		data, err := ioutil.ReadFile(filepath.Join(sink.Directory, name))
+		if err != nil {
+			log.WithFields(log.Fields{
+				"object": name,
+				"error":  err,
+			}).Error("Failed to read object for S3.")
+			continue
+		}
+		object := s3Object{name: name, data: data, variants: map[dataserver.Encoding][]byte{}}
+		if dataserver.Compressible(name) {
+			for _, encoding := range encodings {
+				compressed, err := sink.Precompressed(name, data, encoding)
+				if err != nil {
+					log.WithFields(log.Fields{
+						"object":   name,
+						"encoding": encoding,
+						"error":    err,
+					}).Error("Failed to compress object for S3.")
+					continue
+				}
+				object.variants[encoding] = compressed
+			}
+		}
+		objects = append(objects, object)
+	}
+	return objects
+}
+
+// s3Loop pushes the objects to S3 along with the precompressed variants the bucket is configured for
+func s3Loop(bucket config.S3, objects []s3Object) {
+	encodings := dataserver.EncodingsOf(bucket.Compression)
+	formats := dataserver.FormatsOf(bucket.Formats)
+	userMetaData := map[string]string{"x-amz-acl": "public-read"}
+
+	minioClient, err := newS3Client(bucket)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to create new S3 client.")
+		return
+	}
+
+	for _, object := range objects {
+		if !uploadsFormat(formats, object.name) {
+			continue
+		}
+		contentType := dataserver.ContentType(object.name)
+		err = s3Upload(minioClient, bucket.BucketName, object.name, object.data, minio.PutObjectOptions{ContentType: contentType, UserMetadata: userMetaData})
+		if err != nil {
+			log.WithFields(log.Fields{
+				"object": object.name,
+				"error":  err,
+			}).Error("Failed to upload object to S3.")
+		}
+		for _, encoding := range encodings {
+			compressed, ok := object.variants[encoding]
+			if !ok {
+				continue
+			}
+			err = s3Upload(minioClient, bucket.BucketName, encoding.FileName(object.name), compressed, minio.PutObjectOptions{ContentType: contentType, ContentEncoding: string(encoding), UserMetadata: userMetaData})
+			if err != nil {
+				log.WithFields(log.Fields{
+					"object": encoding.FileName(object.name),
+					"error":  err,
+				}).Error("Failed to upload object to S3.")
+			}
+		}
+	}
+}
+
+// s3Delete removes the files from a bucket along with any precompressed variants
+func s3Delete(bucket config.S3, files []string) {
+	minioClient, err := newS3Client(bucket)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to create new S3 client.")
+		return
+	}
+	for _, file := range files {
+		for _, objectName := range dataserver.VariantNames(file) {
+			err = minioClient.RemoveObject(bucket.BucketName, objectName)
+			if err != nil {
+				log.WithFields(log.Fields{
+					"object": objectName,
+					"error":  err,
+				}).Error("Failed to remove object from S3.")
+				continue
+			}
+			log.WithField("object", objectName).Debug("Successfully removed object from S3.")
+		}
+	}
+}
+
+// purgeBucket erases a CID's member data from every object in a bucket
+func purgeBucket(bucket config.S3, cid int) error {
+	minioClient, err := newS3Client(bucket)
+	if err != nil {
+		return err
+	}
+	return dataserver.PurgeStored(bucketStore{client: minioClient, bucket: bucket.BucketName}, cid)
+}
+
+// bucketStore keeps outputs in an S3 bucket
+type bucketStore struct {
+	client *minio.Client
+	bucket string
+}
+
+// List names every object in the bucket.
+func (b bucketStore) List() ([]string, error) {
+	doneCh := make(chan struct{})
+	defer close(doneCh)
+	var names []string
+	for object := range b.client.ListObjectsV2(b.bucket, "", true, doneCh) {
+		if object.Err != nil {
+			return nil, errors.WithStack(object.Err)
+		}
+		names = append(names, object.Key)
+	}
+	return names, nil
+}
+
+// Read downloads an object as it is stored.
+func (b bucketStore) Read(name string) ([]byte, error) {
+	object, err := b.client.GetObject(b.bucket, name, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	defer object.Close()
+	data, err := ioutil.ReadAll(object)
+	return data, errors.WithStack(err)
+}
+
+// Write uploads an object, or its variant with the encoding.
+func (b bucketStore) Write(name string, data []byte, encoding dataserver.Encoding) error {
+	options := minio.PutObjectOptions{
+		ContentType:  dataserver.ContentType(name),
+		UserMetadata: map[string]string{"x-amz-acl": "public-read"},
+	}
+	if encoding != "" {
+		compressed, err := encoding.Compress(data)
+		if err != nil {
+			return err
+		}
+		data = compressed
+		name = encoding.FileName(name)
+		options.ContentEncoding = string(encoding)
+	}
+	return s3Upload(b.client, b.bucket, name, data, options)
+}
+
+// Remove deletes an object from the bucket.
+func (b bucketStore) Remove(name string) error {
+	return errors.WithStack(b.client.RemoveObject(b.bucket, name))
+}
+
+// newS3Client connects to a bucket's endpoint
+func newS3Client(bucket config.S3) (*minio.Client, error) {
+	minioClient, err := minio.New(bucket.Endpoint, bucket.AccessKeyID, bucket.SecretAccessKey, true)
+	return minioClient, errors.WithStack(err)
+}
+
+// uploadsFormat checks if a bucket takes a file, defaulting to every format written
+func uploadsFormat(formats []dataserver.Format, objectName string) bool {
+	format, ok := dataserver.FormatOf(objectName)
+	if !ok || formats == nil {
+		return true
+	}
+	for _, v := range formats {
+		if v == format {
+			return true
+		}
+	}
+	return false
+}
+
+// s3Upload puts a single object into a bucket
+func s3Upload(minioClient *minio.Client, bucketName string, objectName string, data []byte, opts minio.PutObjectOptions) error {
+	n, err := minioClient.PutObject(bucketName, objectName, bytes.NewReader(data), int64(len(data)), opts)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	log.WithFields(log.Fields{
+		"object": objectName,
+		"size":   n,
+	}).Debug("Successfully uploaded object to S3.")
+	return nil
+}
@@ -0,0 +1,93 @@
+package dataserver
+
+import (
+	"dataserver/internal/pkg/config"
+	"dataserver/internal/pkg/dataserver"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestReadObjects(t *testing.T) {
+	directory, err := ioutil.TempDir("", "objects")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	sink := dataserver.FileSink{Directory: directory, Encodings: []dataserver.Encoding{dataserver.EncodingGzip}}
+	if err := sink.Write("vatsim-data.json", []byte(`{"serial":1}`)); err != nil {
+		t.Fatal(err)
+	}
+	buckets := []config.S3{{Compression: []string{"gzip", "br"}}}
+	objects := readObjects(sink, buckets, []string{"vatsim-data.json", "missing.json"})
+	// The next publish rewrites the files while the objects are still uploading
+	if err := sink.Write("vatsim-data.json", []byte(`{"serial":2}`)); err != nil {
+		t.Fatal(err)
+	}
+	if len(objects) != 1 {
+		t.Fatalf("readObjects() read %d objects, want only the file which exists", len(objects))
+	}
+	if string(objects[0].data) != `{"serial":1}` {
+		t.Errorf("readObjects() data = %s, want the file as it was read", objects[0].data)
+	}
+	for _, encoding := range []dataserver.Encoding{dataserver.EncodingGzip, dataserver.EncodingBrotli} {
+		data, err := encoding.Decompress(objects[0].variants[encoding])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != `{"serial":1}` {
+			t.Errorf("readObjects() %s variant = %s, want the file as it was read", encoding, data)
+		}
+	}
+}
+
+func TestS3BatchMerge(t *testing.T) {
+	names := func(batch s3Batch) []string {
+		var names []string
+		for _, v := range batch.objects {
+			names = append(names, v.name+"="+string(v.data))
+		}
+		return names
+	}
+	tests := []struct {
+		name        string
+		earlier     s3Batch
+		later       s3Batch
+		wantObjects []string
+		wantRemoved []string
+	}{
+		{
+			"Later copy kept",
+			s3Batch{objects: []s3Object{{name: "vatsim-data.json", data: []byte("1")}, {name: "deltas/2.json", data: []byte("2")}}},
+			s3Batch{objects: []s3Object{{name: "vatsim-data.json", data: []byte("3")}, {name: "deltas/3.json", data: []byte("3")}}},
+			[]string{"deltas/2.json=2", "vatsim-data.json=3", "deltas/3.json=3"},
+			nil,
+		},
+		{
+			"Removed before uploading",
+			s3Batch{objects: []s3Object{{name: "deltas/2.json", data: []byte("2")}}},
+			s3Batch{removed: []string{"deltas/2.json"}},
+			nil,
+			[]string{"deltas/2.json"},
+		},
+		{
+			"Uploaded again after removal",
+			s3Batch{removed: []string{"vatsim-data-link.kml"}},
+			s3Batch{objects: []s3Object{{name: "vatsim-data-link.kml", data: []byte("1")}}},
+			[]string{"vatsim-data-link.kml=1"},
+			nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := tt.earlier.merge(tt.later)
+			if got := names(merged); !reflect.DeepEqual(got, tt.wantObjects) {
+				t.Errorf("merge() uploads %v, want %v", got, tt.wantObjects)
+			}
+			if !reflect.DeepEqual(merged.removed, tt.wantRemoved) {
+				t.Errorf("merge() removes %v, want %v", merged.removed, tt.wantRemoved)
+			}
+		})
+	}
+}
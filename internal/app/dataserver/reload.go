@@ -0,0 +1,148 @@
+package dataserver
+
+import (
+	"context"
+	"dataserver/internal/pkg/config"
+	"dataserver/internal/pkg/dataserver"
+	"encoding/json"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+)
+
+// kafkaFlushTimeout is how long a replaced Kafka producer has to deliver its queued payloads, in milliseconds
+const kafkaFlushTimeout = 10000
+
+// reloader reads the configuration again and applies it without dropping the FSD connection
+type reloader struct {
+	path      string
+	context   *dataserver.Context
+	publisher *publisher
+	mutex     sync.Mutex
+}
+
+// reloadResult reports the problems preventing a reload, or the settings which need a restart
+type reloadResult struct {
+	Errors          []string `json:"errors,omitempty"`
+	RestartRequired []string `json:"restartRequired,omitempty"`
+}
+
+// watchSignals reloads the configuration on every SIGHUP until the context is cancelled
+func (r *reloader) watchSignals(ctx context.Context) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	defer signal.Stop(signals)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-signals:
+			log.Info("Received SIGHUP, reloading configuration.")
+			r.reload()
+		}
+	}
+}
+
+// reload applies the log level, Kafka producer, opt-out list and publisher settings of the
+// configuration, keeping the current configuration if it is invalid
+func (r *reloader) reload() reloadResult {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	cfg, errs := readConfig(r.path)
+	if len(errs) > 0 {
+		log.WithField("errors", []string(errs)).Error("Invalid configuration, keeping the current one.")
+		return reloadResult{Errors: errs}
+	}
+	previous := r.context.Configuration()
+
+	log.SetLevel(cfg.Log.Level)
+	if kafkaChanged(previous.Kafka, cfg.Kafka) {
+		replaced := r.context.ReplaceProducer(configureKafka(cfg.Kafka))
+		if replaced != nil {
+			go func() {
+				replaced.Flush(kafkaFlushTimeout)
+				replaced.Close()
+			}()
+		}
+	}
+	r.context.Reconfigure(cfg)
+	r.publisher.reconfigure(cfg)
+
+	restart := restartRequired(previous, cfg)
+	for _, v := range restart {
+		log.WithField("setting", v).Warn("Setting changed but needs a restart to take effect.")
+	}
+	log.Info("Configuration reloaded.")
+	return reloadResult{RestartRequired: restart}
+}
+
+// kafkaChanged checks if the Kafka producer must be replaced, which isn't needed for the client types
+func kafkaChanged(previous config.Kafka, cfg config.Kafka) bool {
+	previous.Types = nil
+	cfg.Types = nil
+	return !reflect.DeepEqual(previous, cfg)
+}
+
+// restartRequired lists the settings which changed but are only read when connecting or starting up
+func restartRequired(previous *config.Config, cfg *config.Config) []string {
+	settings := []struct {
+		name            string
+		previous, value interface{}
+	}{
+		{"fsd", previous.FSD, cfg.FSD},
+		{"data.server.name", previous.Data.Server.Name, cfg.Data.Server.Name},
+		{"api", previous.API, cfg.API},
+		{"rpc", previous.RPC, cfg.RPC},
+		{"sbs", previous.SBS, cfg.SBS},
+		{"admin", previous.Admin, cfg.Admin},
+		{"logbook", previous.Logbook, cfg.Logbook},
+		{"sentry", previous.Sentry, cfg.Sentry},
+	}
+	var restart []string
+	for _, v := range settings {
+		if !reflect.DeepEqual(v.previous, v.value) {
+			restart = append(restart, v.name)
+		}
+	}
+	return restart
+}
+
+// handleReload reloads the configuration on POST /reload, reporting the outcome
+func (r *reloader) handleReload(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed.", http.StatusMethodNotAllowed)
+		return
+	}
+	result := r.reload()
+	status := http.StatusOK
+	if len(result.Errors) > 0 {
+		status = http.StatusUnprocessableEntity
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(result)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to write reload response.")
+	}
+}
+
+// exposeAdmin serves the administration endpoints if a port is configured
+func exposeAdmin(r *reloader) {
+	port := r.context.Configuration().Admin.Port
+	if port == "" {
+		log.Debug("Admin port not defined.")
+		return
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/reload", r.handleReload)
+	err := http.ListenAndServe(":"+port, mux)
+	if err != nil {
+		log.Fatal("Failed to expose admin endpoints.")
+	}
+}
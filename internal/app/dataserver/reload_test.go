@@ -0,0 +1,87 @@
+package dataserver
+
+import (
+	"context"
+	"dataserver/internal/pkg/clock"
+	"dataserver/internal/pkg/dataserver"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestReloadWhilePublishing(t *testing.T) {
+	directory, err := ioutil.TempDir("", "reload")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	path := filepath.Join(directory, "config.yml")
+	err = ioutil.WriteFile(path, []byte(`
+fsd:
+  server:
+    ip: 127.0.0.1
+    port: 6809
+timers:
+  publish: 1ns
+data:
+  server:
+    name: DATA
+    email: data@example.com
+    location: Local
+  file:
+    directory: `+directory+`
+    formats: [protobuf, geojson]
+sentry:
+  enabled: false
+kafka:
+  enabled: false
+s3:
+  enabled: false
+log:
+  level: error
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := loadConfig(path)
+	ds := &dataserver.Context{
+		Config:     cfg,
+		ClientList: &dataserver.ClientList{Mutex: &sync.RWMutex{}},
+		OptOut:     dataserver.NewOptOutList(""),
+		Mutex:      &sync.RWMutex{},
+	}
+	p := newPublisher(cfg, clock.Real{})
+	ctx, cancel := context.WithCancel(context.Background())
+	go p.run(ctx, ds.OptOut)
+	r := &reloader{path: path, context: ds, publisher: p}
+
+	// Each client list is written while the next reload reads the configuration
+	for i := 0; i < 20; i++ {
+		dataserver.Channel <- dataserver.ClientList{PilotData: []dataserver.Pilot{{Callsign: "AAL1"}}}
+		if result := r.reload(); len(result.Errors) > 0 {
+			t.Fatalf("reload() errors = %v", result.Errors)
+		}
+	}
+	cancel()
+	<-p.done
+	for _, v := range []string{"vatsim-data.json", "vatsim-data.pb", "vatsim-data.geojson"} {
+		if _, err := os.Stat(filepath.Join(directory, v)); err != nil {
+			t.Errorf("publisher did not write %s: %v", v, err)
+		}
+	}
+
+	reloaded := make(chan struct{})
+	go func() {
+		r.reload()
+		close(reloaded)
+	}()
+	select {
+	case <-reloaded:
+	case <-time.After(5 * time.Second):
+		t.Errorf("reload() blocked after the publisher stopped")
+	}
+}
@@ -0,0 +1,56 @@
+package dataserver
+
+import (
+	"context"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout bounds how long leaving the FSD network and flushing every output may take
+const shutdownTimeout = 10 * time.Second
+
+// cancelOnSignal cancels the context on SIGTERM or an interrupt.
+// The default handling is restored afterwards, so a second signal exits immediately.
+func cancelOnSignal(cancel context.CancelFunc) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, os.Interrupt)
+	received := <-signals
+	signal.Stop(signals)
+	log.WithField("signal", received).Info("Received signal, shutting down.")
+	cancel()
+}
+
+// within runs a step of the shutdown, moving on if the deadline passes first
+func within(deadline context.Context, step string, f func()) {
+	done := make(chan struct{})
+	go func() {
+		f()
+		close(done)
+	}()
+	select {
+	case <-done:
+		log.WithField("step", step).Debug("Shutdown step finished.")
+	case <-deadline.Done():
+		log.WithField("step", step).Error("Shutdown step timed out.")
+	}
+}
+
+// flushKafka delivers the payloads still queued by the producer before closing it
+func flushKafka(deadline context.Context, producer *kafka.Producer) {
+	if producer == nil {
+		return
+	}
+	timeout := shutdownTimeout
+	if when, ok := deadline.Deadline(); ok {
+		timeout = time.Until(when)
+	}
+	remaining := producer.Flush(int(timeout / time.Millisecond))
+	if remaining > 0 {
+		log.WithField("remaining", remaining).Error("Failed to deliver every Kafka payload.")
+	}
+	producer.Close()
+}
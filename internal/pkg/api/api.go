@@ -0,0 +1,197 @@
+package api
+
+import (
+	"dataserver/internal/pkg/dataserver"
+	"encoding/json"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// API serves the live client list over HTTP.
+type API struct {
+	Context     *dataserver.Context
+	ClientTypes dataserver.ClientTypes
+}
+
+// errorResponse is returned when a request cannot be answered.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// clientResponse is returned when looking up a single callsign.
+type clientResponse struct {
+	Pilot      *dataserver.Pilot `json:"pilot,omitempty"`
+	Controller *dataserver.ATC   `json:"controller,omitempty"`
+}
+
+// memberResponse is returned when looking up every client of a member.
+type memberResponse struct {
+	Pilots      []dataserver.Pilot `json:"pilots"`
+	Controllers []dataserver.ATC   `json:"controllers"`
+}
+
+// Register adds the API routes to a mux.
+func (a *API) Register(mux *http.ServeMux) {
+	mux.HandleFunc("/v1/pilots", readOnly(a.handlePilots))
+	mux.HandleFunc("/v1/controllers", readOnly(a.handleControllers))
+	mux.HandleFunc("/v1/clients/", readOnly(a.handleClient))
+	mux.HandleFunc("/v1/members/", readOnly(a.handleMember))
+	mux.HandleFunc("/v1/events", readOnly(a.handleEvents))
+	mux.HandleFunc("/v1/geo/bbox", readOnly(a.handleBoundingBox))
+	mux.HandleFunc("/v1/geo/radius", readOnly(a.handleRadius))
+	mux.HandleFunc("/v1/geo/nearest", readOnly(a.handleNearest))
+	mux.HandleFunc("/v1/ws", readOnly(a.websocketServer().ServeHTTP))
+}
+
+// readOnly rejects every method but GET and HEAD, as the API never changes anything
+func readOnly(handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, "Method not allowed.")
+			return
+		}
+		handler(w, r)
+	}
+}
+
+// clientList takes a redacted snapshot of the clients this API exposes
+func (a *API) clientList() dataserver.ClientList {
+	clientList := a.Context.ClientList.Snapshot().FilterClientTypes(a.ClientTypes)
+	return a.Context.OptOut.RedactClientList(clientList, "api")
+}
+
+// handlePilots lists pilots matching the server, rating, departure and arrival filters
+func (a *API) handlePilots(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	rating, err := intFilter(query, "rating")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	pilots := []dataserver.Pilot{}
+	for _, v := range a.clientList().PilotData {
+		if !matches(query, "server", v.Server) ||
+			!matches(query, "departure", v.FlightPlan.Departure) ||
+			!matches(query, "arrival", v.FlightPlan.Arrival) ||
+			(rating != nil && *rating != v.Rating) {
+			continue
+		}
+		pilots = append(pilots, v)
+	}
+	writeJSON(w, http.StatusOK, pilots)
+}
+
+// handleControllers lists controllers matching the server, facility, rating and type filters
+func (a *API) handleControllers(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	rating, err := intFilter(query, "rating")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	facility, err := intFilter(query, "facility")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	controllers := []dataserver.ATC{}
+	for _, v := range a.clientList().ATCData {
+		if !matches(query, "server", v.Server) ||
+			!matches(query, "type", string(v.ClientType)) ||
+			(rating != nil && *rating != v.Rating) ||
+			(facility != nil && *facility != v.FacilityType) {
+			continue
+		}
+		controllers = append(controllers, v)
+	}
+	writeJSON(w, http.StatusOK, controllers)
+}
+
+// handleClient looks up a single client by callsign
+func (a *API) handleClient(w http.ResponseWriter, r *http.Request) {
+	callsign := strings.TrimPrefix(r.URL.Path, "/v1/clients/")
+	clientList := a.clientList()
+	for i, v := range clientList.PilotData {
+		if strings.EqualFold(v.Callsign, callsign) {
+			writeJSON(w, http.StatusOK, clientResponse{Pilot: &clientList.PilotData[i]})
+			return
+		}
+	}
+	for i, v := range clientList.ATCData {
+		if strings.EqualFold(v.Callsign, callsign) {
+			writeJSON(w, http.StatusOK, clientResponse{Controller: &clientList.ATCData[i]})
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, "Client not found.")
+}
+
+// handleMember looks up every client connected with a CID
+func (a *API) handleMember(w http.ResponseWriter, r *http.Request) {
+	cid, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/v1/members/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "CID must be a number.")
+		return
+	}
+	member := memberResponse{
+		Pilots:      []dataserver.Pilot{},
+		Controllers: []dataserver.ATC{},
+	}
+	if cid != 0 {
+		clientList := a.clientList()
+		for _, v := range clientList.PilotData {
+			if v.Member.CID == cid {
+				member.Pilots = append(member.Pilots, v)
+			}
+		}
+		for _, v := range clientList.ATCData {
+			if v.Member.CID == cid {
+				member.Controllers = append(member.Controllers, v)
+			}
+		}
+	}
+	if len(member.Pilots) == 0 && len(member.Controllers) == 0 {
+		writeError(w, http.StatusNotFound, "Member not connected.")
+		return
+	}
+	writeJSON(w, http.StatusOK, member)
+}
+
+// matches checks a string field against an optional case insensitive filter
+func matches(query url.Values, key string, value string) bool {
+	filter := query.Get(key)
+	return filter == "" || strings.EqualFold(filter, value)
+}
+
+// intFilter parses an optional numeric filter
+func intFilter(query url.Values, key string) (*int, error) {
+	filter := query.Get(key)
+	if filter == "" {
+		return nil, nil
+	}
+	value, err := strconv.Atoi(filter)
+	if err != nil {
+		return nil, errors.Errorf("Filter %s must be a number.", key)
+	}
+	return &value, nil
+}
+
+// writeJSON encodes a response body
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	err := json.NewEncoder(w).Encode(body)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to write API response.")
+	}
+}
+
+// writeError encodes an error response body
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, errorResponse{Error: message})
+}
@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"dataserver/internal/pkg/dataserver"
+	"dataserver/internal/pkg/geo"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestAPI serves a client list with an opted out pilot and an observer hidden from the API
+func newTestAPI() *API {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := &dataserver.Context{
+		ClientList: &dataserver.ClientList{
+			PilotData: []dataserver.Pilot{
+				{Callsign: "AAL1", Server: "USA", Member: dataserver.MemberData{CID: 1, Name: "One"}, Rating: 1, Latitude: 40.64, Longitude: -73.78,
+					FlightPlan: dataserver.FlightPlan{Departure: "KJFK", Arrival: "EGLL"}, LastUpdated: now},
+				{Callsign: "BAW2", Server: "UK", Member: dataserver.MemberData{CID: 2, Name: "Two"}, Rating: 1, Latitude: 51.5, Longitude: -0.45,
+					FlightPlan: dataserver.FlightPlan{Departure: "EGLL", Arrival: "KJFK"}, LastUpdated: now},
+			},
+			ATCData: []dataserver.ATC{
+				{Callsign: "EGLL_TWR", Server: "UK", ClientType: dataserver.ClientTypeController, Member: dataserver.MemberData{CID: 3, Name: "Three"},
+					Rating: 3, FacilityType: 4, Latitude: 51.47, Longitude: -0.46, LastUpdated: now},
+				{Callsign: "EGLL_OBS", Server: "UK", ClientType: dataserver.ClientTypeObserver, Member: dataserver.MemberData{CID: 4, Name: "Four"},
+					Rating: 1, Latitude: 51.47, Longitude: -0.46, LastUpdated: now},
+			},
+			Mutex: &sync.RWMutex{},
+		},
+		OptOut: &dataserver.OptOutList{CIDs: map[int]bool{2: true}, Mutex: &sync.RWMutex{}},
+		Events: dataserver.NewEventBus(10),
+		Index:  geo.NewIndex(1),
+		Mutex:  &sync.RWMutex{},
+	}
+	for _, v := range c.ClientList.PilotData {
+		c.Index.Update(v.Callsign, geo.Point{Latitude: v.Latitude, Longitude: v.Longitude})
+	}
+	for _, v := range c.ClientList.ATCData {
+		c.Index.Update(v.Callsign, geo.Point{Latitude: v.Latitude, Longitude: v.Longitude})
+	}
+	pilots := c.ClientList.PilotData
+	controllers := c.ClientList.ATCData
+	for _, v := range []interface{}{pilots[0], controllers[1], dataserver.Pilot{Callsign: "BAW2"}, pilots[0]} {
+		event := dataserver.Event{MessageType: "update_position", Data: v}
+		switch client := v.(type) {
+		case dataserver.Pilot:
+			event.Callsign = client.Callsign
+		case dataserver.ATC:
+			event.Callsign = client.Callsign
+		}
+		c.Events.Publish(event)
+	}
+	return &API{
+		Context:     c,
+		ClientTypes: dataserver.ClientTypesOf([]string{"pilot", "controller"}),
+	}
+}
+
+func TestAPI(t *testing.T) {
+	mux := http.NewServeMux()
+	newTestAPI().Register(mux)
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		status   int
+		contains []string
+		excludes []string
+	}{
+		{"Pilots", "GET", "/v1/pilots", 200, []string{"AAL1", "BAW2", `"One"`}, []string{`"Two"`}},
+		{"Pilots by server", "GET", "/v1/pilots?server=usa", 200, []string{"AAL1"}, []string{"BAW2"}},
+		{"Pilots by departure", "GET", "/v1/pilots?departure=egll", 200, []string{"BAW2"}, []string{"AAL1"}},
+		{"Pilots by invalid rating", "GET", "/v1/pilots?rating=x", 400, []string{"rating"}, nil},
+		{"Pilots head", "HEAD", "/v1/pilots", 200, nil, nil},
+		{"Pilots post", "POST", "/v1/pilots", 405, []string{"Method not allowed."}, []string{"AAL1"}},
+		{"Controllers", "GET", "/v1/controllers", 200, []string{"EGLL_TWR"}, []string{"EGLL_OBS"}},
+		{"Controllers by hidden type", "GET", "/v1/controllers?type=observer", 200, []string{"[]"}, []string{"EGLL_OBS"}},
+		{"Controllers by facility", "GET", "/v1/controllers?facility=4", 200, []string{"EGLL_TWR"}, nil},
+		{"Controllers by invalid facility", "GET", "/v1/controllers?facility=x", 400, nil, nil},
+		{"Controllers put", "PUT", "/v1/controllers", 405, nil, nil},
+		{"Client", "GET", "/v1/clients/aal1", 200, []string{`"pilot"`, "AAL1"}, []string{`"controller"`}},
+		{"Client redacted", "GET", "/v1/clients/BAW2", 200, []string{"BAW2"}, []string{`"Two"`}},
+		{"Client controller", "GET", "/v1/clients/EGLL_TWR", 200, []string{`"controller"`, "EGLL_TWR"}, nil},
+		{"Client of hidden type", "GET", "/v1/clients/EGLL_OBS", 404, nil, []string{"EGLL_OBS"}},
+		{"Client unknown", "GET", "/v1/clients/DAL3", 404, nil, nil},
+		{"Client delete", "DELETE", "/v1/clients/AAL1", 405, nil, []string{"AAL1"}},
+		{"Member", "GET", "/v1/members/1", 200, []string{"AAL1"}, []string{"BAW2"}},
+		{"Member redacted", "GET", "/v1/members/2", 404, nil, []string{"BAW2"}},
+		{"Member of hidden type", "GET", "/v1/members/4", 404, nil, []string{"EGLL_OBS"}},
+		{"Member invalid", "GET", "/v1/members/x", 400, nil, nil},
+		{"Bounding box", "GET", "/v1/geo/bbox?bbox=50,-1,52,0", 200, []string{"BAW2", "EGLL_TWR"}, []string{"AAL1", "EGLL_OBS", `"Two"`}},
+		{"Bounding box missing", "GET", "/v1/geo/bbox", 400, nil, nil},
+		{"Bounding box beyond the earth", "GET", "/v1/geo/bbox?bbox=-1e300,-1e300,1e300,1e300", 400, []string{"latitudes"}, nil},
+		{"Bounding box of NaN", "GET", "/v1/geo/bbox?bbox=50,NaN,52,0", 400, []string{"longitudes"}, nil},
+		{"Bounding box infinite", "GET", "/v1/geo/bbox?bbox=-Inf,-1,52,0", 400, []string{"latitudes"}, nil},
+		{"Radius", "GET", "/v1/geo/radius?lat=51.47&lon=-0.46&radius=10", 200, []string{"BAW2", "EGLL_TWR"}, []string{"AAL1", "EGLL_OBS"}},
+		{"Radius invalid", "GET", "/v1/geo/radius?lat=51.47&lon=-0.46&radius=-1", 400, nil, nil},
+		{"Radius of NaN", "GET", "/v1/geo/radius?lat=51.47&lon=-0.46&radius=NaN", 400, nil, nil},
+		{"Radius at NaN", "GET", "/v1/geo/radius?lat=NaN&lon=-0.46&radius=10", 400, []string{"lat"}, nil},
+		{"Nearest", "GET", "/v1/geo/nearest?lat=40&lon=-73&k=1", 200, []string{"AAL1"}, []string{"BAW2"}},
+		{"Nearest invalid", "GET", "/v1/geo/nearest?lat=91&lon=-73&k=1", 400, nil, nil},
+		{"Nearest at NaN", "GET", "/v1/geo/nearest?lat=40&lon=NaN&k=1", 400, []string{"lon"}, nil},
+		{"Nearest post", "POST", "/v1/geo/nearest?lat=40&lon=-73&k=1", 405, nil, nil},
+		{"Events post", "POST", "/v1/events", 405, nil, nil},
+		{"Events expired", "GET", "/v1/events?cursor=9", 410, nil, nil},
+		{"Events beyond the earth", "GET", "/v1/events?bbox=50,-1,52,1000", 400, []string{"longitudes"}, nil},
+		{"WebSocket post", "POST", "/v1/ws", 405, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			body := w.Body.String()
+			if w.Code != tt.status {
+				t.Fatalf("%s %s status = %d, want %d: %s", tt.method, tt.path, w.Code, tt.status, body)
+			}
+			if tt.status == http.StatusMethodNotAllowed && w.Header().Get("Allow") != "GET, HEAD" {
+				t.Errorf("%s %s Allow = %q, want GET, HEAD", tt.method, tt.path, w.Header().Get("Allow"))
+			}
+			for _, v := range tt.contains {
+				if !strings.Contains(body, v) {
+					t.Errorf("%s %s = %s, want it to contain %s", tt.method, tt.path, body, v)
+				}
+			}
+			for _, v := range tt.excludes {
+				if strings.Contains(body, v) {
+					t.Errorf("%s %s = %s, want it without %s", tt.method, tt.path, body, v)
+				}
+			}
+		})
+	}
+}
+
+func TestEvents(t *testing.T) {
+	mux := http.NewServeMux()
+	newTestAPI().Register(mux)
+	tests := []struct {
+		name     string
+		path     string
+		contains []string
+		excludes []string
+	}{
+		{"Resume", "/v1/events?cursor=1", []string{"id: 3\n", "id: 4\n"}, []string{"id: 1\n", "id: 2\n", "EGLL_OBS"}},
+		{"Callsign", "/v1/events?cursor=1&callsign=AAL*", []string{"id: 4\n"}, []string{"id: 3\n"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil).WithContext(ctx))
+			body := w.Body.String()
+			if w.Code != http.StatusOK || w.Header().Get("Content-Type") != "text/event-stream" {
+				t.Fatalf("GET %s = %d %s, want a stream", tt.path, w.Code, w.Header().Get("Content-Type"))
+			}
+			for _, v := range tt.contains {
+				if !strings.Contains(body, v) {
+					t.Errorf("GET %s = %s, want it to contain %q", tt.path, body, v)
+				}
+			}
+			for _, v := range tt.excludes {
+				if strings.Contains(body, v) {
+					t.Errorf("GET %s = %s, want it without %q", tt.path, body, v)
+				}
+			}
+		})
+	}
+}
+
+func TestWebSocket(t *testing.T) {
+	mux := http.NewServeMux()
+	newTestAPI().Register(mux)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	ws, err := websocket.Dial(strings.Replace(server.URL, "http", "ws", 1)+"/v1/ws?cursor=1", "", server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ws.Close()
+	for _, want := range []uint64{3, 4} {
+		var event dataserver.Event
+		if err := websocket.JSON.Receive(ws, &event); err != nil {
+			t.Fatal(err)
+		}
+		if event.ID != want {
+			t.Errorf("Receive() event %d, want %d", event.ID, want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package api
+
+import (
+	"dataserver/internal/pkg/geo"
+	"github.com/pkg/errors"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// maxNearest limits how many clients a nearest query may ask for.
+const maxNearest = 500
+
+// handleBoundingBox lists the clients inside the bbox=south,west,north,east parameter
+func (a *API) handleBoundingBox(w http.ResponseWriter, r *http.Request) {
+	bbox, err := parseBoundingBox(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if bbox == nil {
+		writeError(w, http.StatusBadRequest, "Filter bbox is required.")
+		return
+	}
+	writeJSON(w, http.StatusOK, a.clientList().InBoundingBox(a.Context.Index.InBoundingBox(*bbox)))
+}
+
+// handleRadius lists the clients within radius nautical miles of lat and lon, nearest first
+func (a *API) handleRadius(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	center, err := parsePoint(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	radius, err := strconv.ParseFloat(query.Get("radius"), 64)
+	if err != nil || !(radius > 0) || math.IsInf(radius, 1) {
+		writeError(w, http.StatusBadRequest, "Filter radius must be a positive number of nautical miles.")
+		return
+	}
+	writeJSON(w, http.StatusOK, a.clientList().Nearby(a.Context.Index.WithinRadius(center, radius)))
+}
+
+// handleNearest lists the k clients nearest to lat and lon, nearest first
+func (a *API) handleNearest(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	center, err := parsePoint(query)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	k, err := strconv.Atoi(query.Get("k"))
+	if err != nil || k <= 0 || k > maxNearest {
+		writeError(w, http.StatusBadRequest, "Filter k must be between 1 and "+strconv.Itoa(maxNearest)+".")
+		return
+	}
+	writeJSON(w, http.StatusOK, a.clientList().Nearby(a.Context.Index.Nearest(center, k)))
+}
+
+// parsePoint reads the lat and lon parameters
+func parsePoint(query url.Values) (geo.Point, error) {
+	latitude, err := strconv.ParseFloat(query.Get("lat"), 64)
+	if err != nil || !validLatitude(latitude) {
+		return geo.Point{}, errors.New("Filter lat must be between -90 and 90.")
+	}
+	longitude, err := strconv.ParseFloat(query.Get("lon"), 64)
+	if err != nil || !validLongitude(longitude) {
+		return geo.Point{}, errors.New("Filter lon must be between -180 and 180.")
+	}
+	return geo.Point{Latitude: latitude, Longitude: longitude}, nil
+}
+
+// validLatitude checks a latitude is a number between -90 and 90, which NaN never is
+func validLatitude(latitude float64) bool {
+	return latitude >= -90 && latitude <= 90
+}
+
+// validLongitude checks a longitude is a number between -180 and 180, which NaN never is
+func validLongitude(longitude float64) bool {
+	return longitude >= -180 && longitude <= 180
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"dataserver/internal/pkg/dataserver"
+	"dataserver/internal/pkg/geo"
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"golang.org/x/net/websocket"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// keepAliveInterval is how often an idle stream is written to so proxies keep it open.
+const keepAliveInterval = 30 * time.Second
+
+// parseBoundingBox reads a bbox=south,west,north,east parameter
+func parseBoundingBox(query url.Values) (*geo.BoundingBox, error) {
+	bbox := query.Get("bbox")
+	if bbox == "" {
+		return nil, nil
+	}
+	parts := strings.Split(bbox, ",")
+	if len(parts) != 4 {
+		return nil, errors.New("Filter bbox must be south,west,north,east.")
+	}
+	var values [4]float64
+	for i, v := range parts {
+		value, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return nil, errors.New("Filter bbox must be south,west,north,east.")
+		}
+		values[i] = value
+	}
+	if !validLatitude(values[0]) || !validLatitude(values[2]) {
+		return nil, errors.New("Filter bbox latitudes must be between -90 and 90.")
+	}
+	if !validLongitude(values[1]) || !validLongitude(values[3]) {
+		return nil, errors.New("Filter bbox longitudes must be between -180 and 180.")
+	}
+	return &geo.BoundingBox{South: values[0], West: values[1], North: values[2], East: values[3]}, nil
+}
+
+// parseStreamFilter reads the callsign and bbox filters of a stream request
+func (a *API) parseStreamFilter(query url.Values) (dataserver.EventFilter, error) {
+	bbox, err := parseBoundingBox(query)
+	if err != nil {
+		return dataserver.EventFilter{}, err
+	}
+	return dataserver.NewEventFilter(query.Get("callsign"), bbox, a.ClientTypes)
+}
+
+// parseCursor reads the event to resume after from the Last-Event-ID header or cursor parameter
+func parseCursor(r *http.Request) (uint64, error) {
+	cursor := r.Header.Get("Last-Event-ID")
+	if cursor == "" {
+		cursor = r.URL.Query().Get("cursor")
+	}
+	if cursor == "" {
+		return 0, nil
+	}
+	value, err := strconv.ParseUint(cursor, 10, 64)
+	if err != nil {
+		return 0, errors.New("Cursor must be a number.")
+	}
+	return value, nil
+}
+
+// handleEvents streams client list changes as Server-Sent Events
+func (a *API) handleEvents(w http.ResponseWriter, r *http.Request) {
+	filter, err := a.parseStreamFilter(r.URL.Query())
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	cursor, err := parseCursor(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "Streaming is not supported.")
+		return
+	}
+	backlog, events, ok := a.Context.Events.Subscribe(cursor)
+	if !ok {
+		writeError(w, http.StatusGone, "Cursor is no longer available.")
+		return
+	}
+	defer a.Context.Events.Unsubscribe(events)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	for _, v := range backlog {
+		if filter.Matches(v) {
+			writeServerSentEvent(w, v)
+		}
+	}
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case event, open := <-events:
+			if !open {
+				return
+			}
+			if filter.Matches(event) {
+				writeServerSentEvent(w, event)
+				flusher.Flush()
+			}
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// writeServerSentEvent writes a single event in the text/event-stream format
+func writeServerSentEvent(w io.Writer, event dataserver.Event) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to encode stream event.")
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.MessageType, data)
+}
+
+// websocketServer streams client list changes over a WebSocket, accepting any origin
+func (a *API) websocketServer() websocket.Server {
+	return websocket.Server{
+		Handshake: func(config *websocket.Config, r *http.Request) error {
+			return nil
+		},
+		Handler: a.handleWebSocket,
+	}
+}
+
+// handleWebSocket sends each event as a JSON message until the subscriber disconnects
+func (a *API) handleWebSocket(ws *websocket.Conn) {
+	defer ws.Close()
+	r := ws.Request()
+	filter, err := a.parseStreamFilter(r.URL.Query())
+	if err != nil {
+		websocket.JSON.Send(ws, errorResponse{Error: err.Error()})
+		return
+	}
+	cursor, err := parseCursor(r)
+	if err != nil {
+		websocket.JSON.Send(ws, errorResponse{Error: err.Error()})
+		return
+	}
+	backlog, events, ok := a.Context.Events.Subscribe(cursor)
+	if !ok {
+		websocket.JSON.Send(ws, errorResponse{Error: "Cursor is no longer available."})
+		return
+	}
+	defer a.Context.Events.Unsubscribe(events)
+
+	// Subscribers only listen, so reading just tells us when they have gone
+	closed := make(chan struct{})
+	go func() {
+		io.Copy(ioutil.Discard, ws)
+		close(closed)
+	}()
+
+	for _, v := range backlog {
+		if filter.Matches(v) {
+			if websocket.JSON.Send(ws, v) != nil {
+				return
+			}
+		}
+	}
+	for {
+		select {
+		case event, open := <-events:
+			if !open {
+				return
+			}
+			if filter.Matches(event) && websocket.JSON.Send(ws, event) != nil {
+				return
+			}
+		case <-closed:
+			return
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package clock
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// Clock tells the time and waits for it to pass, so time based logic can be tested deterministically.
+type Clock interface {
+	Now() time.Time
+	After(duration time.Duration) <-chan time.Time
+}
+
+// Real is the system clock.
+type Real struct{}
+
+// Now returns the current time.
+func (Real) Now() time.Time {
+	return time.Now()
+}
+
+// After waits for the duration to pass and then sends the current time.
+func (Real) After(duration time.Duration) <-chan time.Time {
+	return time.After(duration)
+}
+
+// Fake is a clock which only moves when advanced.
+type Fake struct {
+	mutex   sync.Mutex
+	changed *sync.Cond
+	now     time.Time
+	waiters []waiter
+}
+
+// waiter is a channel waiting for the fake clock to reach a time
+type waiter struct {
+	until time.Time
+	c     chan time.Time
+}
+
+// NewFake creates a fake clock stopped at a time.
+func NewFake(now time.Time) *Fake {
+	f := &Fake{now: now}
+	f.changed = sync.NewCond(&f.mutex)
+	return f
+}
+
+// Now returns the fake time.
+func (f *Fake) Now() time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.now
+}
+
+// After sends the fake time once the clock is advanced past the duration.
+func (f *Fake) After(duration time.Duration) <-chan time.Time {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	c := make(chan time.Time, 1)
+	if duration <= 0 {
+		c <- f.now
+		return c
+	}
+	f.waiters = append(f.waiters, waiter{until: f.now.Add(duration), c: c})
+	f.changed.Broadcast()
+	return c
+}
+
+// Advance moves the fake time on, releasing everything waiting until then.
+func (f *Fake) Advance(duration time.Duration) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.now = f.now.Add(duration)
+	waiting := f.waiters[:0]
+	for _, v := range f.waiters {
+		if v.until.After(f.now) {
+			waiting = append(waiting, v)
+			continue
+		}
+		v.c <- f.now
+	}
+	f.waiters = waiting
+	f.changed.Broadcast()
+}
+
+// BlockUntil waits until a number of goroutines are waiting on the fake clock.
+func (f *Fake) BlockUntil(waiters int) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	for len(f.waiters) < waiters {
+		f.changed.Wait()
+	}
+}
+
+// Jitter randomly lengthens or shortens an interval by up to a fraction of it.
+func Jitter(interval time.Duration, fraction float64) time.Duration {
+	if fraction <= 0 {
+		return interval
+	}
+	return interval + time.Duration((rand.Float64()*2-1)*fraction*float64(interval))
+}
@@ -0,0 +1,49 @@
+package clock
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeAdvance(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := NewFake(start)
+	minute := fake.After(time.Minute)
+	hour := fake.After(time.Hour)
+	fake.BlockUntil(2)
+
+	fake.Advance(2 * time.Minute)
+	select {
+	case now := <-minute:
+		if !now.Equal(start.Add(2 * time.Minute)) {
+			t.Errorf("After(time.Minute) sent %v", now)
+		}
+	default:
+		t.Error("After(time.Minute) not released")
+	}
+	select {
+	case <-hour:
+		t.Error("After(time.Hour) released early")
+	default:
+	}
+}
+
+func TestJitter(t *testing.T) {
+	tests := []struct {
+		name     string
+		fraction float64
+		min, max time.Duration
+	}{
+		{"None", 0, time.Minute, time.Minute},
+		{"Tenth", 0.1, 54 * time.Second, 66 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 100; i++ {
+				if got := Jitter(time.Minute, tt.fraction); got < tt.min || got > tt.max {
+					t.Fatalf("Jitter() = %v, want between %v and %v", got, tt.min, tt.max)
+				}
+			}
+		})
+	}
+}
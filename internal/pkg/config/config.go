@@ -4,43 +4,22 @@ import (
 	"github.com/evalphobia/logrus_sentry"
 	"github.com/getsentry/sentry-go"
 	"github.com/olebedev/config"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
 	"io/ioutil"
+	"os"
 )
 
-// Cfg contains all of the necessary configuration data.
-var Cfg *config.Config
-
 // ConfigureKafka sets the Kafka connection up
-func ConfigureKafka() *kafka.Producer {
+func ConfigureKafka(cfg Kafka) *kafka.Producer {
 	log.Debug("Starting Kafka connection.")
-	kafkaServer, err := Cfg.String("kafka.server")
-	if err != nil {
-		log.Fatal("Kafka server not defined.")
-	}
-	kafkaUsername, err := Cfg.String("kafka.credentials.username")
-	if err != nil {
-		log.Fatal("Kafka username not defined.")
-	}
-	kafkaPassword, err := Cfg.String("kafka.credentials.password")
-	if err != nil {
-		log.Fatal("Kafka password not defined.")
-	}
-	kafkaProtocol, err := Cfg.String("kafka.credentials.protocol")
-	if err != nil {
-		log.Fatal("Kafka protocol not defined.")
-	}
-	kafkaMechanism, err := Cfg.String("kafka.credentials.mechanism")
-	if err != nil {
-		log.Fatal("Kafka authentication mechanism not defined.")
-	}
 	producer, err := kafka.NewProducer(&kafka.ConfigMap{
-		"bootstrap.servers":   kafkaServer,
-		"sasl.username":       kafkaUsername,
-		"sasl.password":       kafkaPassword,
-		"security.protocol":   kafkaProtocol,
-		"sasl.mechanism":      kafkaMechanism,
+		"bootstrap.servers":   cfg.Server,
+		"sasl.username":       cfg.Username,
+		"sasl.password":       cfg.Password,
+		"security.protocol":   cfg.Protocol,
+		"sasl.mechanism":      cfg.Mechanism,
 		"go.delivery.reports": false,
 	})
 	if err != nil {
@@ -51,12 +30,9 @@ func ConfigureKafka() *kafka.Producer {
 }
 
 // ConfigureSentry sets up Sentry for panic reporting
-func ConfigureSentry() {
-	dsn, err := Cfg.String("sentry.credentials.dsn")
-	if err != nil {
-		log.Fatal("Sentry DSN not defined.")
-	}
-	err = sentry.Init(sentry.ClientOptions{
+func ConfigureSentry(cfg Sentry) {
+	dsn := cfg.DSN
+	err := sentry.Init(sentry.ClientOptions{
 		Dsn: dsn,
 	})
 	if err != nil {
@@ -73,15 +49,29 @@ func ConfigureSentry() {
 	log.AddHook(hook)
 }
 
-// ReadConfig reads the config file and instantiates the config object
-func ReadConfig() {
-	file, err := ioutil.ReadFile("configs/config.yml")
+// readRaw reads the defaults, the config file at path unless it is empty, and then the environment
+func readRaw(path string) (*config.Config, error) {
+	cfg, err := config.ParseYaml(defaults)
 	if err != nil {
-		log.Fatal("Failed to open configuration file.")
+		return nil, errors.Wrap(err, "Failed to parse default configuration.")
+	}
+	root := cfg.Root.(map[string]interface{})
+	if path != "" {
+		file, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to open configuration file.")
+		}
+		fileConfig, err := config.ParseYaml(string(file))
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to parse configuration file.")
+		}
+		if fileRoot, ok := fileConfig.Root.(map[string]interface{}); ok {
+			merge(root, fileRoot)
+		}
 	}
-	yamlString := string(file)
-	Cfg, err = config.ParseYaml(yamlString)
+	err = applyEnvironment(root, os.Environ())
 	if err != nil {
-		log.Fatal("Failed to parse configuration file.")
+		return nil, errors.Wrap(err, "Failed to read configuration from the environment.")
 	}
+	return cfg, nil
 }
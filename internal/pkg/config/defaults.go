@@ -0,0 +1,124 @@
+package config
+
+// defaults is the configuration every config file and the environment are applied over.
+// It documents every key; keys which are commented out have no default, and either must be
+// set or enable an optional feature when they are.
+//
+// Every key can be overridden by an environment variable named after its path, such as
+// DATASERVER_KAFKA_SERVER for kafka.server. Lists are comma separated. Secrets can be read
+// from a file by adding _FILE, such as DATASERVER_KAFKA_CREDENTIALS_PASSWORD_FILE. Keys without
+// a default can be set too, even for a bucket only defined in the environment, such as
+// DATASERVER_S3_EU_BUCKETNAME for s3.eu.bucketName.
+//
+// The configuration is read again on SIGHUP or POST /reload to the admin port. Changes to
+// fsd, data.server.name, api, rpc, sbs, admin, logbook and sentry need a restart.
+const defaults = `
+fsd:
+  # server:
+  #   ip: address of the FSD server to connect to (required)
+  #   port: port of the FSD server to connect to (required)
+  # hostname sent with our server identification
+  hostname: 127.0.0.1
+  # version: sent with our server identification, defaulting to the version the dataserver was built from
+  writer:
+    # number of packets waiting to be sent before more are dropped
+    queue: 5000
+    # how long writing a packet may take
+    timeout: 10s
+    # packets sent per second, unlimited if zero
+    rate: 200
+    # packets which may be sent at once after a quiet period
+    burst: 500
+  # packet numbers remembered from each server, so a broadcast reaching us along several paths
+  # is only handled once
+  duplicateWindow: 4096
+  # fake controllers connected to request data with, none if empty. Some FSD servers only send
+  # pilot positions within a client's visual range, so several can be placed around the world.
+  # Unset keys take the values below, and the callsign defaults to data.server.name.
+  presences:
+    - rating: 1
+      facility: 1
+      frequency: 99999
+      visualRange: 100
+      latitude: 0
+      longitude: 0
+timers:
+  # how often ADDCLIENT and AD packets keep our client connected
+  keepalive: 30s
+  # how often NOTIFY and SYNC packets keep our server connected
+  notify: 2m
+  # how often every controller is asked for their ATIS
+  atis: 1m
+  # how often clients are checked for timing out
+  timeoutCheck: 5m
+  # how often the data files are written
+  publish: 15s
+  # fraction of every interval but publish randomly added or taken away, from 0 up to 1
+  jitter: 0
+timeout:
+  # how long pilots and controllers may go without sending an update before they are removed
+  pilot: 5m
+  controller: 5m
+data:
+  # server:
+  #   name: callsign of the dataserver on the FSD network (required)
+  #   email: contact address sent with our server identification (required)
+  #   location: location sent with our server identification (required)
+  file:
+    # directory: directory every data file is written to (required)
+    # client types written to the data files
+    types: [pilot, controller, observer, supervisor, unknown]
+    # precompressed variants written next to every data file but kmz and protobuf, from gzip and br
+    compression: []
+    # additional formats, from geojson, kml, kmz, dump1090, protobuf and msgpack
+    formats: []
+  delta:
+    # number of delta files kept and listed in the manifest
+    history: 20
+  # privacy:
+  #   optout: file listing the CIDs redacted from every output, one per line
+  kml:
+    # link: URL of the KML or KMZ file the published network link refreshes
+    # number of positions kept for each pilot's track, none if zero
+    tracks: 0
+api:
+  # port: serve the API on its own port instead of next to the metrics on 2112
+  types: [pilot, controller, observer, supervisor, unknown]
+  stream:
+    # number of events kept for streams resuming from a cursor
+    history: 1000
+  geo:
+    # size in degrees of the spatial index cells
+    cellSize: 1
+rpc:
+  # port: serve the gRPC service on this port
+  types: [pilot, controller, observer, supervisor, unknown]
+# sbs:
+#   port: stream BaseStation messages on this port
+# admin:
+#   port: serve the administration endpoints, such as POST /reload, on this port
+log:
+  # level of the messages logged, from panic, fatal, error, warn, info, debug and trace
+  level: info
+# logbook:
+#   path: SQLite database completed sessions are recorded in
+sentry:
+  # report errors to Sentry
+  enabled: true
+  # credentials:
+  #   dsn: Sentry project errors are reported to (required if enabled)
+kafka:
+  # publish client updates to Kafka
+  enabled: true
+  # server: Kafka bootstrap server (required if enabled)
+  # credentials:
+  #   username, password, protocol and mechanism used to authenticate (required if enabled)
+  types: [pilot, controller, observer, supervisor, unknown]
+s3:
+  # upload the data files to S3
+  enabled: true
+  # <region>: every bucket the data files are uploaded to, which can't be named enabled
+  #   endpoint, accessKeyID, secretAccessKey, bucketName, region
+  #   compression: precompressed variants uploaded, reusing those in the data directory, from gzip and br
+  #   formats: additional formats uploaded, defaulting to every format written
+`
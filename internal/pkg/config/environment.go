@@ -0,0 +1,185 @@
+package config
+
+import (
+	"github.com/pkg/errors"
+	"io/ioutil"
+	"strings"
+)
+
+// Environment variables overriding keys start with envPrefix, and read the value from a file if they end with fileSuffix.
+const (
+	envPrefix  = "DATASERVER_"
+	fileSuffix = "_FILE"
+)
+
+// optionalKeys are the keys without a default, which variables are matched against before they are set.
+// A * stands for a name chosen in the configuration, such as a bucket's, and true marks lists.
+var optionalKeys = map[string]bool{
+	"fsd.server.ip":               false,
+	"fsd.server.port":             false,
+	"fsd.version":                 false,
+	"data.server.name":            false,
+	"data.server.email":           false,
+	"data.server.location":        false,
+	"data.file.directory":         false,
+	"data.privacy.optout":         false,
+	"data.kml.link":               false,
+	"api.port":                    false,
+	"rpc.port":                    false,
+	"sbs.port":                    false,
+	"admin.port":                  false,
+	"logbook.path":                false,
+	"sentry.credentials.dsn":      false,
+	"kafka.server":                false,
+	"kafka.credentials.username":  false,
+	"kafka.credentials.password":  false,
+	"kafka.credentials.protocol":  false,
+	"kafka.credentials.mechanism": false,
+	"s3.*.endpoint":               false,
+	"s3.*.accessKeyID":            false,
+	"s3.*.secretAccessKey":        false,
+	"s3.*.bucketName":             false,
+	"s3.*.region":                 false,
+	"s3.*.compression":            true,
+	"s3.*.formats":                true,
+}
+
+// merge applies every key of overlay to base, replacing lists and values and merging maps
+func merge(base map[string]interface{}, overlay map[string]interface{}) {
+	for k, v := range overlay {
+		baseMap, baseIsMap := base[k].(map[string]interface{})
+		overlayMap, overlayIsMap := v.(map[string]interface{})
+		if baseIsMap && overlayIsMap {
+			merge(baseMap, overlayMap)
+			continue
+		}
+		base[k] = v
+	}
+}
+
+// leafKeys lists the path of every value, treating lists as a single value
+func leafKeys(source map[string]interface{}, base []string, keys map[string][]string) {
+	for k, v := range source {
+		path := append(append([]string{}, base...), k)
+		if m, ok := v.(map[string]interface{}); ok {
+			leafKeys(m, path, keys)
+			continue
+		}
+		keys[strings.ToUpper(strings.Join(path, "_"))] = path
+	}
+}
+
+// applyEnvironment overrides keys with DATASERVER_ environment variables.
+// Variables matching no existing key set the optional key they name, so they can enable optional features.
+func applyEnvironment(root map[string]interface{}, environ []string) error {
+	keys := map[string][]string{}
+	leafKeys(root, nil, keys)
+
+	values := map[string]string{}
+	files := map[string]string{}
+	for _, v := range environ {
+		parts := strings.SplitN(v, "=", 2)
+		if len(parts) != 2 || !strings.HasPrefix(parts[0], envPrefix) {
+			continue
+		}
+		name := strings.TrimPrefix(parts[0], envPrefix)
+		if strings.HasSuffix(name, fileSuffix) {
+			files[strings.TrimSuffix(name, fileSuffix)] = parts[1]
+			continue
+		}
+		values[name] = parts[1]
+	}
+	for name, path := range files {
+		if _, ok := values[name]; ok {
+			return errors.Errorf("both %s%s and %s%s%s are set", envPrefix, name, envPrefix, name, fileSuffix)
+		}
+		value, err := ioutil.ReadFile(path)
+		if err != nil {
+			return errors.Wrapf(err, "failed to read %s%s%s", envPrefix, name, fileSuffix)
+		}
+		values[name] = strings.TrimRight(string(value), "\r\n")
+	}
+
+	for name, value := range values {
+		path, ok := keys[name]
+		list := false
+		if !ok {
+			path, list = optionalKey(root, name)
+		}
+		setValue(root, path, value, list)
+	}
+	return nil
+}
+
+// optionalKey finds the optional key a variable names, reporting if it is a list.
+// Names in the key take the case of a name already configured, and are lower case otherwise.
+// Variables naming no optional key set a lower case key.
+func optionalKey(root map[string]interface{}, name string) ([]string, bool) {
+	for key, list := range optionalKeys {
+		path := strings.Split(key, ".")
+		wildcard := -1
+		for i, v := range path {
+			if v == "*" {
+				wildcard = i
+			}
+		}
+		if wildcard < 0 {
+			if strings.ToUpper(strings.Join(path, "_")) == name {
+				return path, list
+			}
+			continue
+		}
+		prefix := strings.ToUpper(strings.Join(path[:wildcard], "_")) + "_"
+		suffix := "_" + strings.ToUpper(strings.Join(path[wildcard+1:], "_"))
+		if len(name) <= len(prefix)+len(suffix) || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) {
+			continue
+		}
+		resolved := append([]string{}, path...)
+		resolved[wildcard] = configuredName(root, path[:wildcard], name[len(prefix):len(name)-len(suffix)])
+		return resolved, list
+	}
+	return strings.Split(strings.ToLower(name), "_"), false
+}
+
+// configuredName finds the name below a path matching a variable regardless of case, or lower cases it
+func configuredName(root map[string]interface{}, path []string, name string) string {
+	node := root
+	for _, k := range path {
+		next, ok := node[k].(map[string]interface{})
+		if !ok {
+			return strings.ToLower(name)
+		}
+		node = next
+	}
+	for k := range node {
+		if strings.EqualFold(k, name) {
+			return k
+		}
+	}
+	return strings.ToLower(name)
+}
+
+// setValue sets a key from an environment variable, splitting values of list keys on commas
+func setValue(root map[string]interface{}, path []string, value string, list bool) {
+	node := root
+	for _, k := range path[:len(path)-1] {
+		next, ok := node[k].(map[string]interface{})
+		if !ok {
+			next = map[string]interface{}{}
+			node[k] = next
+		}
+		node = next
+	}
+	key := path[len(path)-1]
+	if _, isList := node[key].([]interface{}); isList || list {
+		values := []interface{}{}
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+		node[key] = values
+		return
+	}
+	node[key] = value
+}
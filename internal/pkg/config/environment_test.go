@@ -0,0 +1,77 @@
+package config
+
+import (
+	"github.com/olebedev/config"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestApplyEnvironment(t *testing.T) {
+	directory, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	secret := filepath.Join(directory, "password")
+	err = ioutil.WriteFile(secret, []byte("hunter2\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := config.ParseYaml(defaults)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := cfg.Root.(map[string]interface{})
+	file, err := config.ParseYaml("s3:\n  us:\n    accessKeyID: file\napi:\n  types: [pilot]\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	merge(root, file.Root.(map[string]interface{}))
+	err = applyEnvironment(root, []string{
+		"DATASERVER_KAFKA_SERVER=kafka:9092",
+		"DATASERVER_KAFKA_CREDENTIALS_PASSWORD_FILE=" + secret,
+		"DATASERVER_S3_US_ACCESSKEYID=env",
+		"DATASERVER_API_GEO_CELLSIZE=0.5",
+		"DATASERVER_DATA_FILE_FORMATS=geojson, kmz",
+		"HOME=/root",
+	})
+	if err != nil {
+		t.Fatalf("applyEnvironment() error = %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want interface{}
+	}{
+		{"kafka.server", "kafka:9092"},
+		{"kafka.credentials.password", "hunter2"},
+		{"s3.us.accessKeyID", "env"},
+		{"api.geo.cellSize", "0.5"},
+		{"api.types", []interface{}{"pilot"}},
+		{"data.file.formats", []interface{}{"geojson", "kmz"}},
+		{"data.delta.history", 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			got, err := config.Get(root, tt.path)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	err = applyEnvironment(root, []string{
+		"DATASERVER_KAFKA_SERVER=kafka:9092",
+		"DATASERVER_KAFKA_SERVER_FILE=" + secret,
+	})
+	if err == nil {
+		t.Errorf("applyEnvironment() should fail when a key and its file are both set")
+	}
+}
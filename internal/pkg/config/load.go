@@ -0,0 +1,583 @@
+package config
+
+import (
+	"fmt"
+	"github.com/olebedev/config"
+	log "github.com/sirupsen/logrus"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Config is the typed configuration of the dataserver, loaded once at startup.
+type Config struct {
+	FSD     FSD
+	Timers  Timers
+	Timeout Timeout
+	Data    Data
+	API     API
+	RPC     RPC
+	SBS     SBS
+	Admin   Admin
+	Log     Log
+	Logbook Logbook
+	Sentry  Sentry
+	Kafka   Kafka
+	// S3 is empty if S3 is disabled.
+	S3 []S3
+}
+
+// FSD is the FSD server we connect to and how we appear on it.
+type FSD struct {
+	IP       string
+	Port     string
+	Hostname string
+	Version  string
+	Writer   Writer
+	// DuplicateWindow is how many packet numbers are remembered from each server to drop copies with.
+	DuplicateWindow int
+	// Presences is empty if no fake controller is connected.
+	Presences []Presence
+}
+
+// Writer configures how packets are sent to FSD.
+type Writer struct {
+	Queue   int
+	Timeout time.Duration
+	Rate    int
+	Burst   int
+}
+
+// Presence is a fake controller connected to request data with.
+type Presence struct {
+	Callsign    string
+	Rating      int
+	Facility    int
+	Frequency   int
+	VisualRange int
+	Latitude    float64
+	Longitude   float64
+}
+
+// Timers configures how often the dataserver talks to FSD and publishes.
+type Timers struct {
+	Keepalive    time.Duration
+	Notify       time.Duration
+	ATIS         time.Duration
+	TimeoutCheck time.Duration
+	Publish      time.Duration
+	// Jitter is the fraction of every interval but Publish randomly added or taken away.
+	Jitter float64
+}
+
+// Timeout configures how long clients may go without sending an update.
+type Timeout struct {
+	Pilot      time.Duration
+	Controller time.Duration
+}
+
+// Data configures the dataserver's identity and the data files it writes.
+type Data struct {
+	Server  Server
+	File    File
+	Delta   Delta
+	Privacy Privacy
+	KML     KML
+}
+
+// Server identifies the dataserver on the FSD network.
+type Server struct {
+	Name     string
+	Email    string
+	Location string
+}
+
+// File configures the data files written to the data directory.
+type File struct {
+	Directory   string
+	Types       []string
+	Compression []string
+	Formats     []string
+}
+
+// Delta configures the delta files written between data files.
+type Delta struct {
+	History int
+}
+
+// Privacy configures the members redacted from every output.
+type Privacy struct {
+	OptOut string
+}
+
+// KML configures the KML and KMZ outputs.
+type KML struct {
+	Link   string
+	Tracks int
+}
+
+// API configures the HTTP API.
+type API struct {
+	Port          string
+	Types         []string
+	StreamHistory int
+	GeoCellSize   float64
+}
+
+// RPC configures the gRPC service.
+type RPC struct {
+	Port  string
+	Types []string
+}
+
+// SBS configures the BaseStation listener.
+type SBS struct {
+	Port string
+}
+
+// Admin configures the administration endpoints.
+type Admin struct {
+	Port string
+}
+
+// Log configures logging.
+type Log struct {
+	Level log.Level
+}
+
+// Logbook configures the session logbook.
+type Logbook struct {
+	Path string
+}
+
+// Sentry configures error reporting.
+type Sentry struct {
+	Enabled bool
+	DSN     string
+}
+
+// Kafka configures the Kafka producer.
+type Kafka struct {
+	Enabled   bool
+	Server    string
+	Types     []string
+	Username  string
+	Password  string
+	Protocol  string
+	Mechanism string
+}
+
+// S3 is a bucket the data files are uploaded to.
+type S3 struct {
+	Name            string
+	Endpoint        string
+	AccessKeyID     string
+	SecretAccessKey string
+	BucketName      string
+	Region          string
+	Compression     []string
+	// Formats is nil if every format written is uploaded.
+	Formats []string
+}
+
+// Errors lists every problem found in the configuration.
+type Errors []string
+
+// Error joins every problem.
+func (e Errors) Error() string {
+	return strings.Join(e, "\n")
+}
+
+// reader collects typed values and every problem found reading them
+type reader struct {
+	cfg    *config.Config
+	errors Errors
+}
+
+// Load reads the defaults, the config file at path unless it is empty, and the environment,
+// returning every problem found together.
+func Load(path string) (*Config, error) {
+	raw, err := readRaw(path)
+	if err != nil {
+		return nil, err
+	}
+	r := &reader{cfg: raw}
+	cfg := &Config{
+		FSD: FSD{
+			IP:       r.required("fsd.server.ip"),
+			Port:     r.required("fsd.server.port"),
+			Hostname: r.required("fsd.hostname"),
+			Version:  r.optional("fsd.version"),
+			Writer: Writer{
+				Queue:   r.int("fsd.writer.queue", 1),
+				Timeout: r.duration("fsd.writer.timeout"),
+				Rate:    r.int("fsd.writer.rate", 0),
+				Burst:   r.int("fsd.writer.burst", 1),
+			},
+			DuplicateWindow: r.int("fsd.duplicateWindow", 1),
+			Presences:       r.presences("fsd.presences"),
+		},
+		Timers: Timers{
+			Keepalive:    r.duration("timers.keepalive"),
+			Notify:       r.duration("timers.notify"),
+			ATIS:         r.duration("timers.atis"),
+			TimeoutCheck: r.duration("timers.timeoutCheck"),
+			Publish:      r.duration("timers.publish"),
+			Jitter:       r.fraction("timers.jitter"),
+		},
+		Timeout: Timeout{
+			Pilot:      r.duration("timeout.pilot"),
+			Controller: r.duration("timeout.controller"),
+		},
+		Data: Data{
+			Server: Server{
+				Name:     r.required("data.server.name"),
+				Email:    r.required("data.server.email"),
+				Location: r.required("data.server.location"),
+			},
+			File: File{
+				Directory:   r.required("data.file.directory"),
+				Types:       r.list("data.file.types"),
+				Compression: r.list("data.file.compression"),
+				Formats:     r.list("data.file.formats"),
+			},
+			Delta: Delta{
+				History: r.int("data.delta.history", 0),
+			},
+			Privacy: Privacy{
+				OptOut: r.optional("data.privacy.optout"),
+			},
+			KML: KML{
+				Link:   r.optional("data.kml.link"),
+				Tracks: r.int("data.kml.tracks", 0),
+			},
+		},
+		API: API{
+			Port:          r.port("api.port"),
+			Types:         r.list("api.types"),
+			StreamHistory: r.int("api.stream.history", 1),
+			GeoCellSize:   r.float("api.geo.cellSize"),
+		},
+		RPC: RPC{
+			Port:  r.port("rpc.port"),
+			Types: r.list("rpc.types"),
+		},
+		SBS: SBS{
+			Port: r.port("sbs.port"),
+		},
+		Admin: Admin{
+			Port: r.port("admin.port"),
+		},
+		Log: Log{
+			Level: r.level("log.level"),
+		},
+		Logbook: Logbook{
+			Path: r.optional("logbook.path"),
+		},
+		Sentry: r.sentry("sentry"),
+		Kafka:  r.kafka("kafka"),
+		S3:     r.buckets("s3"),
+	}
+	if cfg.FSD.Version == "" {
+		cfg.FSD.Version = buildVersion()
+	}
+	r.namePresences("fsd.presences", cfg.FSD.Presences, cfg.Data.Server.Name)
+	if len(r.errors) > 0 {
+		return cfg, r.errors
+	}
+	return cfg, nil
+}
+
+// buildVersion is the version of the module the dataserver was built from
+func buildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok || info.Main.Version == "" || info.Main.Version == "(devel)" {
+		return "devel"
+	}
+	return info.Main.Version
+}
+
+// fail records a problem with a key
+func (r *reader) fail(path string, format string, args ...interface{}) {
+	r.errors = append(r.errors, path+": "+fmt.Sprintf(format, args...))
+}
+
+// exists checks if a key is set
+func (r *reader) exists(path string) bool {
+	_, err := config.Get(r.cfg.Root, path)
+	return err == nil
+}
+
+// required reads a string which must be set
+func (r *reader) required(path string) string {
+	value, err := r.cfg.String(path)
+	if err != nil || value == "" {
+		r.fail(path, "not defined")
+	}
+	return value
+}
+
+// optional reads a string which enables a feature when set
+func (r *reader) optional(path string) string {
+	if !r.exists(path) {
+		return ""
+	}
+	value, err := r.cfg.String(path)
+	if err != nil {
+		r.fail(path, "must be a string")
+	}
+	return value
+}
+
+// port reads an optional port number
+func (r *reader) port(path string) string {
+	if !r.exists(path) {
+		return ""
+	}
+	port, err := r.cfg.Int(path)
+	if err != nil || port < 1 || port > 65535 {
+		r.fail(path, "must be a port number")
+		return ""
+	}
+	return fmt.Sprint(port)
+}
+
+// bool reads a switch
+func (r *reader) bool(path string) bool {
+	value, err := r.cfg.Bool(path)
+	if err != nil {
+		r.fail(path, "must be true or false")
+	}
+	return value
+}
+
+// level reads a log level
+func (r *reader) level(path string) log.Level {
+	value, err := r.cfg.String(path)
+	if err != nil {
+		r.fail(path, "must be a string")
+		return log.InfoLevel
+	}
+	level, err := log.ParseLevel(value)
+	if err != nil {
+		r.fail(path, "must be a log level")
+		return log.InfoLevel
+	}
+	return level
+}
+
+// int reads a number which is at least min
+func (r *reader) int(path string, min int) int {
+	value, err := r.cfg.Int(path)
+	if err != nil {
+		r.fail(path, "must be a whole number")
+		return 0
+	}
+	if value < min {
+		r.fail(path, "must be at least %d", min)
+	}
+	return value
+}
+
+// float reads a positive number
+func (r *reader) float(path string) float64 {
+	value, err := r.cfg.Float64(path)
+	if err != nil {
+		r.fail(path, "must be a number")
+		return 0
+	}
+	if value <= 0 {
+		r.fail(path, "must be greater than zero")
+	}
+	return value
+}
+
+// intOr reads an optional number which is at least min, returning def if it is not set
+func (r *reader) intOr(path string, def int, min int) int {
+	if !r.exists(path) {
+		return def
+	}
+	return r.int(path, min)
+}
+
+// coordinate reads an optional latitude or longitude no further than limit from zero
+func (r *reader) coordinate(path string, limit float64) float64 {
+	if !r.exists(path) {
+		return 0
+	}
+	value, err := r.cfg.Float64(path)
+	if err != nil {
+		r.fail(path, "must be a number")
+		return 0
+	}
+	if value < -limit || value > limit {
+		r.fail(path, "must be between %v and %v", -limit, limit)
+	}
+	return value
+}
+
+// fraction reads a number from 0 up to but excluding 1
+func (r *reader) fraction(path string) float64 {
+	value, err := r.cfg.Float64(path)
+	if err != nil {
+		r.fail(path, "must be a number")
+		return 0
+	}
+	if value < 0 || value >= 1 {
+		r.fail(path, "must be at least 0 and less than 1")
+		return 0
+	}
+	return value
+}
+
+// duration reads a positive duration such as 30s or 5m
+func (r *reader) duration(path string) time.Duration {
+	value, err := r.cfg.String(path)
+	if err != nil {
+		r.fail(path, "must be a duration")
+		return 0
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		r.fail(path, "must be a duration")
+		return 0
+	}
+	if duration <= 0 {
+		r.fail(path, "must be greater than zero")
+	}
+	return duration
+}
+
+// list reads a list of strings, returning nil if it is not set
+func (r *reader) list(path string) []string {
+	if !r.exists(path) {
+		return nil
+	}
+	list, err := r.cfg.List(path)
+	if err != nil {
+		r.fail(path, "must be a list")
+		return nil
+	}
+	values := make([]string, 0, len(list))
+	for _, v := range list {
+		value, ok := v.(string)
+		if !ok {
+			r.fail(path, "must only contain strings")
+			continue
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
+// presences reads every fake controller, filling in the defaults of unset keys
+func (r *reader) presences(path string) []Presence {
+	if !r.exists(path) {
+		return nil
+	}
+	list, err := r.cfg.List(path)
+	if err != nil {
+		r.fail(path, "must be a list of presences")
+		return nil
+	}
+	presences := make([]Presence, 0, len(list))
+	for i, v := range list {
+		prefix := fmt.Sprintf("%s.%d.", path, i)
+		if _, ok := v.(map[string]interface{}); !ok {
+			r.fail(prefix[:len(prefix)-1], "must be a map")
+			continue
+		}
+		presences = append(presences, Presence{
+			Callsign:    r.optional(prefix + "callsign"),
+			Rating:      r.intOr(prefix+"rating", 1, 0),
+			Facility:    r.intOr(prefix+"facility", 1, 0),
+			Frequency:   r.intOr(prefix+"frequency", 99999, 0),
+			VisualRange: r.intOr(prefix+"visualRange", 100, 0),
+			Latitude:    r.coordinate(prefix+"latitude", 90),
+			Longitude:   r.coordinate(prefix+"longitude", 180),
+		})
+	}
+	return presences
+}
+
+// namePresences gives presences without a callsign the server's name, and checks every callsign is unique
+func (r *reader) namePresences(path string, presences []Presence, name string) {
+	seen := map[string]int{}
+	for i := range presences {
+		if presences[i].Callsign == "" {
+			presences[i].Callsign = name
+		}
+		if first, found := seen[presences[i].Callsign]; found {
+			r.fail(fmt.Sprintf("%s.%d.callsign", path, i), "same as %s.%d.callsign", path, first)
+			continue
+		}
+		seen[presences[i].Callsign] = i
+	}
+}
+
+// sentry reads the Sentry settings, which are only required if it is enabled
+func (r *reader) sentry(path string) Sentry {
+	sentry := Sentry{Enabled: r.bool(path + ".enabled")}
+	if sentry.Enabled {
+		sentry.DSN = r.required(path + ".credentials.dsn")
+	}
+	return sentry
+}
+
+// kafka reads the Kafka settings, which are only required if it is enabled
+func (r *reader) kafka(path string) Kafka {
+	kafka := Kafka{
+		Enabled: r.bool(path + ".enabled"),
+		Types:   r.list(path + ".types"),
+	}
+	if kafka.Enabled {
+		kafka.Server = r.required(path + ".server")
+		kafka.Username = r.required(path + ".credentials.username")
+		kafka.Password = r.required(path + ".credentials.password")
+		kafka.Protocol = r.required(path + ".credentials.protocol")
+		kafka.Mechanism = r.required(path + ".credentials.mechanism")
+	}
+	return kafka
+}
+
+// buckets reads every S3 bucket in name order, or none if S3 is disabled
+func (r *reader) buckets(path string) []S3 {
+	if !r.bool(path + ".enabled") {
+		return nil
+	}
+	buckets, err := r.cfg.Map(path)
+	if err != nil {
+		r.fail(path, "must be a map of buckets")
+		return nil
+	}
+	var names []string
+	for k := range buckets {
+		if k != "enabled" {
+			names = append(names, k)
+		}
+	}
+	sort.Strings(names)
+	var s3 []S3
+	for _, k := range names {
+		prefix := path + "." + k + "."
+		s3 = append(s3, S3{
+			Name:            k,
+			Endpoint:        r.required(prefix + "endpoint"),
+			AccessKeyID:     r.required(prefix + "accessKeyID"),
+			SecretAccessKey: r.required(prefix + "secretAccessKey"),
+			BucketName:      r.required(prefix + "bucketName"),
+			Region:          r.optional(prefix + "region"),
+			Compression:     r.list(prefix + "compression"),
+			Formats:         r.list(prefix + "formats"),
+		})
+	}
+	return s3
+}
+
+// Check records a problem found by a package validating its own settings.
+func (e *Errors) Check(path string, err error) {
+	if err != nil {
+		*e = append(*e, path+": "+err.Error())
+	}
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	directory, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	path := filepath.Join(directory, "config.yml")
+	err = ioutil.WriteFile(path, []byte(`
+fsd:
+  server:
+    ip: 127.0.0.1
+    port: 6809
+data:
+  server:
+    name: DATA
+    email: data@example.com
+  delta:
+    history: -1
+api:
+  port: 99999
+s3:
+  us:
+    endpoint: sfo2.digitaloceanspaces.com
+    bucketName: vatsim-data-us
+    formats: [protobuf]
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	want := Errors{
+		"data.server.location: not defined",
+		"data.file.directory: not defined",
+		"data.delta.history: must be at least 0",
+		"api.port: must be a port number",
+		"sentry.credentials.dsn: not defined",
+		"kafka.server: not defined",
+		"kafka.credentials.username: not defined",
+		"kafka.credentials.password: not defined",
+		"kafka.credentials.protocol: not defined",
+		"kafka.credentials.mechanism: not defined",
+		"s3.us.accessKeyID: not defined",
+		"s3.us.secretAccessKey: not defined",
+	}
+	if !reflect.DeepEqual(err, want) {
+		t.Errorf("Load() error = %#v, want %#v", err, want)
+	}
+	if cfg.FSD.Port != "6809" || cfg.API.StreamHistory != 1000 || len(cfg.S3) != 1 || cfg.S3[0].Formats[0] != "protobuf" {
+		t.Errorf("Load() = %+v", cfg)
+	}
+}
+
+func TestLoadWithoutIntegrations(t *testing.T) {
+	directory, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	path := filepath.Join(directory, "config.yml")
+	err = ioutil.WriteFile(path, []byte(`
+fsd:
+  server:
+    ip: 127.0.0.1
+    port: 6809
+data:
+  server:
+    name: DATA
+    email: data@example.com
+    location: Local
+  file:
+    directory: data
+sentry:
+  enabled: false
+kafka:
+  enabled: false
+s3:
+  enabled: false
+  us:
+    endpoint: sfo2.digitaloceanspaces.com
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Sentry.Enabled || cfg.Kafka.Enabled || cfg.S3 != nil {
+		t.Errorf("Load() = %+v", cfg)
+	}
+}
+
+func TestLoadPresences(t *testing.T) {
+	directory, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+
+	tests := []struct {
+		name      string
+		presences string
+		want      []Presence
+		errors    Errors
+	}{
+		{
+			"Default",
+			"",
+			[]Presence{{Callsign: "DATA", Rating: 1, Facility: 1, Frequency: 99999, VisualRange: 100}},
+			nil,
+		},
+		{
+			"None",
+			"presences: []",
+			[]Presence{},
+			nil,
+		},
+		{
+			"Several",
+			"presences: [{visualRange: 600}, {callsign: DATA_2, latitude: 51.5, longitude: -0.1}]",
+			[]Presence{
+				{Callsign: "DATA", Rating: 1, Facility: 1, Frequency: 99999, VisualRange: 600},
+				{Callsign: "DATA_2", Rating: 1, Facility: 1, Frequency: 99999, VisualRange: 100, Latitude: 51.5, Longitude: -0.1},
+			},
+			nil,
+		},
+		{
+			"Invalid",
+			"presences: [{latitude: 91}, {callsign: DATA}]",
+			nil,
+			Errors{
+				"fsd.presences.0.latitude: must be between -90 and 90",
+				"fsd.presences.1.callsign: same as fsd.presences.0.callsign",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(directory, "config.yml")
+			err := ioutil.WriteFile(path, []byte(`
+fsd:
+  server:
+    ip: 127.0.0.1
+    port: 6809
+  `+tt.presences+`
+data:
+  server:
+    name: DATA
+    email: data@example.com
+    location: Local
+  file:
+    directory: data
+sentry:
+  enabled: false
+kafka:
+  enabled: false
+`), 0644)
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg, err := Load(path)
+			if tt.errors != nil {
+				if !reflect.DeepEqual(err, tt.errors) {
+					t.Errorf("Load() error = %#v, want %#v", err, tt.errors)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if !reflect.DeepEqual(cfg.FSD.Presences, tt.want) {
+				t.Errorf("Load() presences = %+v, want %+v", cfg.FSD.Presences, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadBucketFromEnvironment(t *testing.T) {
+	directory, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	path := filepath.Join(directory, "config.yml")
+	err = ioutil.WriteFile(path, []byte(`
+fsd:
+  server:
+    ip: 127.0.0.1
+    port: 6809
+data:
+  server:
+    name: DATA
+    email: data@example.com
+    location: Local
+  file:
+    directory: data
+sentry:
+  enabled: false
+kafka:
+  enabled: false
+s3:
+  US:
+    endpoint: sfo2.digitaloceanspaces.com
+    bucketName: vatsim-data-us
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	environment := map[string]string{
+		"DATASERVER_S3_US_ACCESSKEYID":          "us-key",
+		"DATASERVER_S3_US_SECRETACCESSKEY":      "us-secret",
+		"DATASERVER_S3_EU_WEST_ENDPOINT":        "ams3.digitaloceanspaces.com",
+		"DATASERVER_S3_EU_WEST_ACCESSKEYID":     "eu-key",
+		"DATASERVER_S3_EU_WEST_SECRETACCESSKEY": "eu-secret",
+		"DATASERVER_S3_EU_WEST_BUCKETNAME":      "vatsim-data-eu",
+		"DATASERVER_S3_EU_WEST_COMPRESSION":     "gzip, br",
+	}
+	for k, v := range environment {
+		os.Setenv(k, v)
+		defer os.Unsetenv(k)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := []S3{
+		{Name: "US", Endpoint: "sfo2.digitaloceanspaces.com", AccessKeyID: "us-key", SecretAccessKey: "us-secret", BucketName: "vatsim-data-us"},
+		{Name: "eu_west", Endpoint: "ams3.digitaloceanspaces.com", AccessKeyID: "eu-key", SecretAccessKey: "eu-secret", BucketName: "vatsim-data-eu", Compression: []string{"gzip", "br"}},
+	}
+	if !reflect.DeepEqual(cfg.S3, want) {
+		t.Errorf("Load() buckets = %+v, want %+v", cfg.S3, want)
+	}
+}
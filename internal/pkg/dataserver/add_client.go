@@ -1,6 +1,7 @@
 package dataserver
 
 import (
+	"dataserver/internal/pkg/config"
 	"dataserver/internal/pkg/fsd"
 	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
@@ -9,6 +10,7 @@ import (
 
 // ClientList is a list of all clients currently connected to the network.
 type ClientList struct {
+	Serial    uint64        `json:"serial,omitempty"`
 	PilotData []Pilot       `json:"pilots"`
 	ATCData   []ATC         `json:"controllers"`
 	Mutex     *sync.RWMutex `json:"-"`
@@ -20,73 +22,161 @@ type MemberData struct {
 	Name string `json:"name"`
 }
 
+// Snapshot copies the client list so it can be read without holding the lock.
+func (c *ClientList) Snapshot() ClientList {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.copy()
+}
+
+// SnapshotAt copies the client list along with the ID of the last event applied to it.
+// Changes are published while the client list is locked, so resuming events from that ID
+// neither misses nor repeats a change.
+func (c *Context) SnapshotAt() (ClientList, uint64) {
+	c.ClientList.Mutex.RLock()
+	defer c.ClientList.Mutex.RUnlock()
+	return c.ClientList.copy(), c.Events.Cursor()
+}
+
+// copy copies the client list while it is locked
+func (c *ClientList) copy() ClientList {
+	snapshot := ClientList{
+		PilotData: make([]Pilot, len(c.PilotData)),
+		ATCData:   make([]ATC, len(c.ATCData)),
+		Mutex:     c.Mutex,
+	}
+	copy(snapshot.PilotData, c.PilotData)
+	copy(snapshot.ATCData, c.ATCData)
+	return snapshot
+}
+
 // HandleAddClient adds a client to the Client list and updates the JSON file.
 func (c *Context) HandleAddClient(fields []string) error {
 	addClient, err := fsd.DeserializeAddClient(fields)
 	if err != nil {
 		return err
 	}
+	var ended []Session
+	// Deferred before the unlock, so a session ended by changing type is recorded once the lock is released
+	defer func() { c.recordSessions(ended) }()
 	c.ClientList.Mutex.Lock()
 	defer c.ClientList.Mutex.Unlock()
-	if addClient.Type == 1 {
-		data := Pilot{
-			Server:   addClient.Server,
-			Callsign: addClient.Callsign,
-			Member: MemberData{
-				CID:  addClient.CID,
-				Name: addClient.RealName,
-			},
-		}
-		*&c.ClientList.PilotData = append(c.ClientList.PilotData, data)
-		kafkaPush(c.Producer, data, "add_client")
-	} else if addClient.Type == 2 {
-		data := ATC{
-			Server:   addClient.Server,
-			Callsign: addClient.Callsign,
-			Rating:   addClient.Rating,
-			Member: MemberData{
-				CID:  addClient.CID,
-				Name: addClient.RealName,
-			},
+	clientType := classifyClient(addClient.Type, addClient.Rating, facilityUnknown)
+	previousServer, found := c.ClientList.serverOf(addClient.Callsign)
+	member := MemberData{
+		CID:  addClient.CID,
+		Name: addClient.RealName,
+	}
+	if clientType == ClientTypePilot {
+		ended = c.upsertPilot(addClient, member)
+	} else {
+		ended = c.upsertATC(addClient, member, clientType)
+	}
+	if !found {
+		totalConnections.With(prometheus.Labels{"server": addClient.Server}).Inc()
+	} else {
+		duplicateAddClients.Inc()
+		if previousServer != addClient.Server {
+			totalConnections.With(prometheus.Labels{"server": previousServer}).Dec()
+			totalConnections.With(prometheus.Labels{"server": addClient.Server}).Inc()
+			c.reportCallsignCollision(addClient.Callsign, addClient.CID, previousServer, addClient.Server)
 		}
-		*&c.ClientList.ATCData = append(c.ClientList.ATCData, data)
-		kafkaPush(c.Producer, data, "add_client")
 	}
-	totalConnections.With(prometheus.Labels{"server": addClient.Server}).Inc()
+	c.checkDuplicateCID(addClient.CID)
 	log.WithFields(log.Fields{
 		"callsign": addClient.Callsign,
 		"name":     addClient.RealName,
+		"type":     clientType,
 		"server":   addClient.Source,
 	}).Debug("Add client packet received.")
 	Channel <- *c.ClientList
 	return nil
 }
 
-// sendAddClient sends the packet to connect our fake client
-func (c *Context) sendAddClient(name string) {
+// upsertPilot updates the pilot with the callsign or adds them if they are not yet known,
+// returning the session of the controller they replace to be recorded once the lock is released
+func (c *Context) upsertPilot(addClient fsd.AddClient, member MemberData) []Session {
+	var ended []Session
+	if previous, found := c.ClientList.removeATC(addClient.Callsign); found {
+		ended = append(ended, c.endSession(previous.session(SessionEndChangedType, c.Clock.Now())))
+	}
+	for i, v := range c.ClientList.PilotData {
+		if v.Callsign == addClient.Callsign {
+			*&c.ClientList.PilotData[i].Server = addClient.Server
+			*&c.ClientList.PilotData[i].Member = member
+			*&c.ClientList.PilotData[i].Rating = addClient.Rating
+			c.publish(c.ClientList.PilotData[i], "add_client")
+			return ended
+		}
+	}
+	data := Pilot{
+		Server:    addClient.Server,
+		Callsign:  addClient.Callsign,
+		Member:    member,
+		Rating:    addClient.Rating,
+		LogonTime: c.Clock.Now().UTC(),
+	}
+	*&c.ClientList.PilotData = append(c.ClientList.PilotData, data)
+	c.publish(data, "add_client")
+	return ended
+}
+
+// upsertATC updates the controller with the callsign or adds them if they are not yet known,
+// returning the session of the pilot they replace to be recorded once the lock is released
+func (c *Context) upsertATC(addClient fsd.AddClient, member MemberData, clientType ClientType) []Session {
+	var ended []Session
+	if previous, found := c.ClientList.removePilot(addClient.Callsign); found {
+		ended = append(ended, c.endSession(previous.session(SessionEndChangedType, c.Clock.Now())))
+	}
+	for i, v := range c.ClientList.ATCData {
+		if v.Callsign == addClient.Callsign {
+			*&c.ClientList.ATCData[i].Server = addClient.Server
+			*&c.ClientList.ATCData[i].Member = member
+			*&c.ClientList.ATCData[i].Rating = addClient.Rating
+			if !v.LastUpdated.IsZero() {
+				clientType = classifyClient(addClient.Type, addClient.Rating, v.FacilityType)
+			}
+			*&c.ClientList.ATCData[i].ClientType = clientType
+			c.publish(c.ClientList.ATCData[i], "add_client")
+			return ended
+		}
+	}
+	data := ATC{
+		Server:     addClient.Server,
+		Callsign:   addClient.Callsign,
+		ClientType: clientType,
+		Rating:     addClient.Rating,
+		Member:     member,
+		LogonTime:  c.Clock.Now().UTC(),
+	}
+	*&c.ClientList.ATCData = append(c.ClientList.ATCData, data)
+	c.publish(data, "add_client")
+	return ended
+}
+
+// sendAddClient sends the packet to connect one of our fake clients
+func (c *Context) sendAddClient(name string, presence config.Presence) {
 	addClient := fsd.AddClient{
 		Base: fsd.Base{
-			Destination:  "*",
-			Source:       name,
-			PacketNumber: fsd.PdCount,
-			HopCount:     1,
+			Destination: fsd.Everyone,
+			Source:      name,
+			HopCount:    1,
 		},
 		CID:              0,
 		Server:           name,
-		Callsign:         name,
+		Callsign:         presence.Callsign,
 		Type:             2,
-		Rating:           1,
+		Rating:           presence.Rating,
 		ProtocolRevision: 100,
-		RealName:         name,
+		RealName:         presence.Callsign,
 		SimType:          -1,
 		Hidden:           1,
 	}
-	err := fsd.Send(c.Consumer, addClient.Serialize())
+	err := c.Writer.Send(&addClient)
 	if err != nil {
 		log.WithFields(log.Fields{
-			"connection": c.Consumer,
-			"error":      err,
-		}).Fatal("Failed to send ADDCLIENT packet to FSD server.")
+			"packet": addClient.Serialize(),
+			"error":  err,
+		}).Error("Failed to send ADDCLIENT packet to FSD server.")
 	}
-	log.WithField("packet", addClient.Serialize()).Debug("Successfully sent ADDCLIENT packet to server.")
 }
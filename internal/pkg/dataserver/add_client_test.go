@@ -0,0 +1,153 @@
+package dataserver
+
+import (
+	"dataserver/internal/pkg/clock"
+	"dataserver/internal/pkg/config"
+	"dataserver/internal/pkg/fsd"
+	"dataserver/internal/pkg/logbook"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// eventTypes lists the message types published so far
+func eventTypes(bus *EventBus) []string {
+	var types []string
+	for _, v := range bus.history {
+		types = append(types, v.MessageType)
+	}
+	return types
+}
+
+func TestUpsert(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	c := newTestContext(&config.Config{}, fake)
+	member := MemberData{CID: 1, Name: "One"}
+	pilot := fsd.AddClient{Callsign: "AAL1", Server: "SERVER1", Type: 1, Rating: 1}
+	atc := fsd.AddClient{Callsign: "AAL1", Server: "SERVER1", Type: 2, Rating: 5}
+
+	tests := []struct {
+		name        string
+		upsert      func()
+		pilots      int
+		controllers int
+		events      []string
+	}{
+		{"Pilot added", func() { c.upsertPilot(pilot, member) }, 1, 0, []string{"add_client"}},
+		{"Pilot updated", func() { c.upsertPilot(pilot, member) }, 1, 0, []string{"add_client", "add_client"}},
+		{"Pilot becomes controller", func() { c.upsertATC(atc, member, ClientTypeController) }, 0, 1, []string{"add_client", "add_client", "remove_client", "add_client"}},
+		{"Controller becomes pilot", func() { c.upsertPilot(pilot, member) }, 1, 0, []string{"add_client", "add_client", "remove_client", "add_client", "remove_client", "add_client"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake.Advance(time.Minute)
+			tt.upsert()
+			if len(c.ClientList.PilotData) != tt.pilots || len(c.ClientList.ATCData) != tt.controllers {
+				t.Errorf("client list has %d pilots and %d controllers, want %d and %d", len(c.ClientList.PilotData), len(c.ClientList.ATCData), tt.pilots, tt.controllers)
+			}
+			got := eventTypes(c.Events)
+			if len(got) != len(tt.events) {
+				t.Fatalf("published %v, want %v", got, tt.events)
+			}
+			for i := range got {
+				if got[i] != tt.events[i] {
+					t.Errorf("published %v, want %v", got, tt.events)
+				}
+			}
+		})
+	}
+	if logon := c.ClientList.PilotData[0].LogonTime; !logon.Equal(start.Add(4 * time.Minute)) {
+		t.Errorf("LogonTime = %v, want the logon as a pilot again %v", logon, start.Add(4*time.Minute))
+	}
+	removed, ok := c.Events.history[4].Data.(Session)
+	if !ok || removed.Reason != SessionEndChangedType || removed.ClientType != ClientTypeController {
+		t.Errorf("remove_client event = %+v, want the controller entry ending with %v", c.Events.history[4].Data, SessionEndChangedType)
+	}
+	end := start.Add(4 * time.Minute)
+	if !removed.LogoffTime.Equal(end) || removed.Duration != 60 || !c.Events.history[4].Timestamp.Equal(end) {
+		t.Errorf("remove_client event at %v ended %v after %ds, want the clock's %v after 60s", c.Events.history[4].Timestamp, removed.LogoffTime, removed.Duration, end)
+	}
+}
+
+func TestUpsertRecordsChangedType(t *testing.T) {
+	directory, err := ioutil.TempDir("", "logbook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	sessions, err := logbook.Open(filepath.Join(directory, "logbook.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sessions.Close()
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	c := newTestContext(&config.Config{}, fake)
+	c.Logbook = sessions
+	member := MemberData{CID: 1, Name: "One"}
+	c.recordSessions(c.upsertPilot(fsd.AddClient{Callsign: "AAL1", Type: 1, Rating: 1}, member))
+	fake.Advance(time.Hour)
+	c.recordSessions(c.upsertATC(fsd.AddClient{Callsign: "AAL1", Type: 2, Rating: 5}, member, ClientTypeController))
+
+	hours, err := sessions.HoursByCID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hours) != 1 || hours[0].Type != string(ClientTypePilot) || hours[0].Sessions != 1 || hours[0].Duration != time.Hour {
+		t.Errorf("HoursByCID() = %+v, want the pilot session recorded for an hour", hours)
+	}
+	if logon := c.ClientList.ATCData[0].LogonTime; !logon.Equal(start.Add(time.Hour)) {
+		t.Errorf("LogonTime = %v, want the logon as a controller %v", logon, start.Add(time.Hour))
+	}
+}
+
+func TestCheckDuplicateCID(t *testing.T) {
+	c := newTestContext(&config.Config{}, clock.NewFake(time.Now()))
+	add := func(callsign string) func() {
+		return func() {
+			c.ClientList.PilotData = append(c.ClientList.PilotData, Pilot{Callsign: callsign, Member: MemberData{CID: 1}})
+			c.checkDuplicateCID(1)
+		}
+	}
+	remove := func(callsign string) func() {
+		return func() {
+			pilot, _ := c.ClientList.removePilot(callsign)
+			c.endSession(pilot.session(SessionEndRemoved, c.Clock.Now()))
+		}
+	}
+	resend := func() { c.checkDuplicateCID(1) }
+
+	tests := []struct {
+		name     string
+		action   func()
+		reported int
+	}{
+		{"Single callsign", add("AAL1"), 0},
+		{"Second callsign", add("AAL2"), 1},
+		{"ADDCLIENT sent again", resend, 1},
+		{"Third callsign", add("AAL3"), 2},
+		{"Callsign removed", remove("AAL3"), 2},
+		{"ADDCLIENT sent again after removal", resend, 2},
+		{"Callsign reconnects", add("AAL3"), 3},
+		{"Back to one callsign", func() { remove("AAL3")(); remove("AAL2")() }, 3},
+		{"Duplicated again", add("AAL2"), 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action()
+			reported := 0
+			for _, v := range eventTypes(c.Events) {
+				if v == "duplicate_cid" {
+					reported++
+				}
+			}
+			if reported != tt.reported {
+				t.Errorf("duplicate_cid reported %d times, want %d", reported, tt.reported)
+			}
+		})
+	}
+}
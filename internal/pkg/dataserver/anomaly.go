@@ -0,0 +1,142 @@
+package dataserver
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// Anomaly describes a client which conflicts with another client on the network.
+type Anomaly struct {
+	CID       int      `json:"cid"`
+	Callsign  string   `json:"callsign,omitempty"`
+	Callsigns []string `json:"callsigns,omitempty"`
+	Servers   []string `json:"servers,omitempty"`
+}
+
+// serverOf finds the server a callsign is connected to
+func (c *ClientList) serverOf(callsign string) (string, bool) {
+	for _, v := range c.PilotData {
+		if v.Callsign == callsign {
+			return v.Server, true
+		}
+	}
+	for _, v := range c.ATCData {
+		if v.Callsign == callsign {
+			return v.Server, true
+		}
+	}
+	return "", false
+}
+
+// removePilot removes the pilot with the callsign if they exist
+func (c *ClientList) removePilot(callsign string) (Pilot, bool) {
+	for i, v := range c.PilotData {
+		if v.Callsign == callsign {
+			*&c.PilotData = append(c.PilotData[:i], c.PilotData[i+1:]...)
+			return v, true
+		}
+	}
+	return Pilot{}, false
+}
+
+// removeATC removes the controller with the callsign if they exist
+func (c *ClientList) removeATC(callsign string) (ATC, bool) {
+	for i, v := range c.ATCData {
+		if v.Callsign == callsign {
+			*&c.ATCData = append(c.ATCData[:i], c.ATCData[i+1:]...)
+			return v, true
+		}
+	}
+	return ATC{}, false
+}
+
+// reportCallsignCollision records a callsign which has moved between servers
+func (c *Context) reportCallsignCollision(callsign string, cid int, previousServer string, server string) {
+	callsignCollisions.Inc()
+	anomaly := Anomaly{
+		CID:      cid,
+		Callsign: callsign,
+		Servers:  []string{previousServer, server},
+	}
+	c.publish(anomaly, "callsign_collision")
+	log.WithFields(log.Fields{
+		"callsign": callsign,
+		"servers":  anomaly.Servers,
+	}).Warn("Callsign connected to more than one server.")
+}
+
+// checkDuplicateCID records a CID which is connected under several callsigns,
+// unless it was already reported under every one of them
+func (c *Context) checkDuplicateCID(cid int) {
+	if cid == 0 {
+		return
+	}
+	anomaly := Anomaly{
+		CID: cid,
+	}
+	for _, v := range c.ClientList.PilotData {
+		if v.Member.CID == cid {
+			anomaly.Callsigns = append(anomaly.Callsigns, v.Callsign)
+			anomaly.Servers = append(anomaly.Servers, v.Server)
+		}
+	}
+	for _, v := range c.ClientList.ATCData {
+		if v.Member.CID == cid {
+			anomaly.Callsigns = append(anomaly.Callsigns, v.Callsign)
+			anomaly.Servers = append(anomaly.Servers, v.Server)
+		}
+	}
+	if len(anomaly.Callsigns) < 2 {
+		delete(c.reportedCIDs, cid)
+		return
+	}
+	reported := c.reportedCIDs[cid]
+	if c.reportedCIDs == nil {
+		c.reportedCIDs = map[int][]string{}
+	}
+	c.reportedCIDs[cid] = anomaly.Callsigns
+	if containsAll(reported, anomaly.Callsigns) {
+		return
+	}
+	duplicateCIDs.Inc()
+	c.publish(anomaly, "duplicate_cid")
+	log.WithFields(log.Fields{
+		"callsigns": anomaly.Callsigns,
+		"servers":   anomaly.Servers,
+	}).Warn("CID connected under more than one callsign.")
+}
+
+// forgetDuplicateCID stops remembering a callsign a CID was reported under once it disconnects
+func (c *Context) forgetDuplicateCID(cid int, callsign string) {
+	reported, ok := c.reportedCIDs[cid]
+	if !ok {
+		return
+	}
+	var remaining []string
+	for _, v := range reported {
+		if v != callsign {
+			remaining = append(remaining, v)
+		}
+	}
+	if len(remaining) < 2 {
+		delete(c.reportedCIDs, cid)
+		return
+	}
+	c.reportedCIDs[cid] = remaining
+}
+
+// containsAll checks every callsign is in the set
+func containsAll(set []string, callsigns []string) bool {
+	for _, v := range callsigns {
+		found := false
+		for _, w := range set {
+			if v == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -1,15 +1,18 @@
 package dataserver
 
 import (
+	"dataserver/internal/pkg/config"
 	"dataserver/internal/pkg/fsd"
+	"dataserver/internal/pkg/geo"
 	log "github.com/sirupsen/logrus"
 	"time"
 )
 
-// ATC is data about individual controllers on the network.
+// ATC is data about individual controllers, observers and other controller-side clients on the network.
 type ATC struct {
 	Server       string     `json:"server"`
 	Callsign     string     `json:"callsign"`
+	ClientType   ClientType `json:"type"`
 	Member       MemberData `json:"member"`
 	Rating       int        `json:"rating"`
 	Frequency    int        `json:"frequency"`
@@ -19,6 +22,7 @@ type ATC struct {
 	Longitude    float64    `json:"longitude"`
 	ATIS         string     `json:"atis"`
 	ATISReceived bool       `json:"-"`
+	LogonTime    time.Time  `json:"logon_time"`
 	LastUpdated  time.Time  `json:"last_updated"`
 }
 
@@ -32,14 +36,18 @@ func (c *Context) HandleATCData(fields []string) error {
 	defer c.ClientList.Mutex.Unlock()
 	for i, v := range c.ClientList.ATCData {
 		if v.Callsign == atcData.Callsign {
-			timeBetweenATCUpdates.Observe(time.Since(*&c.ClientList.ATCData[i].LastUpdated).Seconds())
+			timeBetweenATCUpdates.Observe(c.Clock.Now().Sub(*&c.ClientList.ATCData[i].LastUpdated).Seconds())
 			*&c.ClientList.ATCData[i].Frequency = atcData.Frequency
 			*&c.ClientList.ATCData[i].FacilityType = atcData.FacilityType
+			if v.ClientType != ClientTypeUnknown {
+				*&c.ClientList.ATCData[i].ClientType = classifyClient(fsdTypeATC, v.Rating, atcData.FacilityType)
+			}
 			*&c.ClientList.ATCData[i].VisualRange = atcData.VisualRange
 			*&c.ClientList.ATCData[i].Latitude = atcData.Latitude
 			*&c.ClientList.ATCData[i].Longitude = atcData.Longitude
-			*&c.ClientList.ATCData[i].LastUpdated = time.Now().UTC()
-			kafkaPush(c.Producer, c.ClientList.ATCData[i], "update_controller_data")
+			*&c.ClientList.ATCData[i].LastUpdated = c.Clock.Now().UTC()
+			c.Index.Update(v.Callsign, geo.Point{Latitude: atcData.Latitude, Longitude: atcData.Longitude})
+			c.publish(c.ClientList.ATCData[i], "update_controller_data")
 			break
 		}
 	}
@@ -52,29 +60,27 @@ func (c *Context) HandleATCData(fields []string) error {
 	return nil
 }
 
-// sendATCData sends fake ATC data for our client
-func (c *Context) sendATCData(name string) {
+// sendATCData sends fake ATC data for one of our clients
+func (c *Context) sendATCData(name string, presence config.Presence) {
 	atcData := fsd.ATCData{
 		Base: fsd.Base{
-			Destination:  "*",
-			Source:       name,
-			PacketNumber: fsd.PdCount,
-			HopCount:     1,
+			Destination: fsd.Everyone,
+			Source:      name,
+			HopCount:    1,
 		},
-		Callsign:     name,
-		Frequency:    99999,
-		FacilityType: 1,
-		VisualRange:  100,
-		Rating:       1,
-		Latitude:     0.00000,
-		Longitude:    0.00000,
+		Callsign:     presence.Callsign,
+		Frequency:    presence.Frequency,
+		FacilityType: presence.Facility,
+		VisualRange:  presence.VisualRange,
+		Rating:       presence.Rating,
+		Latitude:     presence.Latitude,
+		Longitude:    presence.Longitude,
 	}
-	err := fsd.Send(c.Consumer, atcData.Serialize())
+	err := c.Writer.Send(&atcData)
 	if err != nil {
 		log.WithFields(log.Fields{
-			"connection": c.Consumer,
-			"error":      err,
-		}).Fatal("Failed to send AD packet to FSD server.")
+			"packet": atcData.Serialize(),
+			"error":  err,
+		}).Error("Failed to send AD packet to FSD server.")
 	}
-	log.WithField("packet", atcData.Serialize()).Debug("Successfully sent AD packet to server.")
 }
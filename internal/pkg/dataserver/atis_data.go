@@ -22,7 +22,7 @@ func (c *Context) HandleATISData(fields []string) error {
 			case "E":
 				*&c.ClientList.ATCData[i].ATISReceived = true
 			}
-			kafkaPush(c.Producer, c.ClientList.ATCData[i], "update_controller_data")
+			c.publish(c.ClientList.ATCData[i], "update_controller_data")
 		}
 	}
 	log.WithFields(log.Fields{
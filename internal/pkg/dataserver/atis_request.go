@@ -5,22 +5,26 @@ import (
 	log "github.com/sirupsen/logrus"
 )
 
-// sendATISRequest sends the ATIS request packet to all ATC clients
-func (c *Context) sendATISRequest(name string) {
+// sendATISRequest sends the ATIS request packet to all ATC clients, asking for replies to a fake client
+func (c *Context) sendATISRequest(name string, from string) {
+	c.ClientList.Mutex.RLock()
+	defer c.ClientList.Mutex.RUnlock()
 	for _, v := range c.ClientList.ATCData {
 		atisRequest := fsd.ATISRequest{
 			Base: fsd.Base{
-				Destination:  v.Callsign,
-				Source:       name,
-				PacketNumber: fsd.PdCount,
-				HopCount:     1,
+				Destination: fsd.ClientDestination(v.Callsign),
+				Source:      name,
+				Routing:     fsd.Unicast,
+				HopCount:    1,
 			},
-			From: name,
+			From: from,
 		}
-		err := fsd.Send(c.Consumer, atisRequest.Serialize())
+		err := c.Writer.Send(&atisRequest)
 		if err != nil {
-			log.WithField("packet", atisRequest.Serialize()).Error("Failed to request ATIS.")
+			log.WithFields(log.Fields{
+				"packet": atisRequest.Serialize(),
+				"error":  err,
+			}).Error("Failed to request ATIS.")
 		}
-		log.WithField("packet", atisRequest.Serialize()).Debug("Successfully requested ATIS.")
 	}
 }
@@ -0,0 +1,127 @@
+package dataserver
+
+import (
+	"github.com/pkg/errors"
+)
+
+// ClientType classifies a client by the role it has on the network.
+type ClientType string
+
+// The client types we store.
+const (
+	ClientTypePilot      ClientType = "pilot"
+	ClientTypeController ClientType = "controller"
+	ClientTypeObserver   ClientType = "observer"
+	ClientTypeSupervisor ClientType = "supervisor"
+	ClientTypeUnknown    ClientType = "unknown"
+)
+
+// FSD client types sent in the ADDCLIENT packet.
+const (
+	fsdTypePilot = 1
+	fsdTypeATC   = 2
+)
+
+// Ratings and facilities which change how a controller-side client is classified.
+const (
+	ratingObserver      = 1
+	ratingSupervisor    = 11
+	ratingAdministrator = 12
+	facilityObserver    = 0
+	facilityUnknown     = -1
+)
+
+// ClientTypes is a set of client types an output includes.
+type ClientTypes map[ClientType]bool
+
+// AllClientTypes includes every client type.
+var AllClientTypes = ClientTypes{
+	ClientTypePilot:      true,
+	ClientTypeController: true,
+	ClientTypeObserver:   true,
+	ClientTypeSupervisor: true,
+	ClientTypeUnknown:    true,
+}
+
+// classifyClient determines the client type from the FSD type, rating and facility.
+func classifyClient(fsdType int, rating int, facility int) ClientType {
+	switch {
+	case fsdType == fsdTypePilot:
+		return ClientTypePilot
+	case fsdType != fsdTypeATC:
+		return ClientTypeUnknown
+	case rating == ratingSupervisor || rating == ratingAdministrator:
+		return ClientTypeSupervisor
+	case rating == ratingObserver || facility == facilityObserver:
+		return ClientTypeObserver
+	}
+	return ClientTypeController
+}
+
+// ClientTypesOf converts the client type names of an output, defaulting to all of them.
+// Unknown names are skipped as they are reported when the configuration is validated.
+func ClientTypesOf(names []string) ClientTypes {
+	if names == nil {
+		return AllClientTypes
+	}
+	clientTypes := ClientTypes{}
+	for _, v := range names {
+		if AllClientTypes[ClientType(v)] {
+			clientTypes[ClientType(v)] = true
+		}
+	}
+	return clientTypes
+}
+
+// validateClientTypes checks every name is a known client type
+func validateClientTypes(names []string) error {
+	for _, v := range names {
+		if !AllClientTypes[ClientType(v)] {
+			return errors.Errorf("unknown client type %s", v)
+		}
+	}
+	return nil
+}
+
+// Includes checks if the client type is part of the set.
+func (t ClientTypes) Includes(clientType ClientType) bool {
+	if t == nil {
+		return true
+	}
+	return t[clientType]
+}
+
+// FilterClientTypes returns a copy of the client list with only the included client types.
+func (c ClientList) FilterClientTypes(clientTypes ClientTypes) ClientList {
+	filtered := ClientList{
+		Mutex: c.Mutex,
+	}
+	if clientTypes.Includes(ClientTypePilot) {
+		filtered.PilotData = make([]Pilot, len(c.PilotData))
+		copy(filtered.PilotData, c.PilotData)
+	} else {
+		filtered.PilotData = []Pilot{}
+	}
+	filtered.ATCData = []ATC{}
+	for _, v := range c.ATCData {
+		if clientTypes.Includes(v.ClientType) {
+			filtered.ATCData = append(filtered.ATCData, v)
+		}
+	}
+	return filtered
+}
+
+// IncludesPayload checks if an event payload belongs to an included client type.
+func (t ClientTypes) IncludesPayload(data interface{}) bool {
+	switch v := data.(type) {
+	case Pilot:
+		return t.Includes(ClientTypePilot)
+	case ATC:
+		return t.Includes(v.ClientType)
+	case Session:
+		return t.Includes(v.ClientType)
+	case FlightPlan:
+		return t.Includes(ClientTypePilot)
+	}
+	return true
+}
@@ -0,0 +1,134 @@
+package dataserver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/andybalholm/brotli"
+	"github.com/pkg/errors"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+)
+
+// Encoding is a compression an output is precompressed with, named as in Content-Encoding.
+type Encoding string
+
+// Encodings which can be written next to each output.
+const (
+	EncodingGzip   Encoding = "gzip"
+	EncodingBrotli Encoding = "br"
+)
+
+// encodingExtensions are appended to the name of each precompressed file
+var encodingExtensions = map[Encoding]string{
+	EncodingGzip:   ".gz",
+	EncodingBrotli: ".br",
+}
+
+// compressedExtensions are the outputs which are never precompressed, KMZ being a zip archive
+// and protobuf too dense to shrink much further
+var compressedExtensions = map[string]bool{
+	".kmz": true,
+	".pb":  true,
+}
+
+// Compressible checks if a file is worth writing precompressed variants of.
+func Compressible(name string) bool {
+	return !compressedExtensions[filepath.Ext(name)]
+}
+
+// EncodingsOf converts the encoding names of an output, skipping unknown names.
+func EncodingsOf(names []string) []Encoding {
+	var encodings []Encoding
+	for _, v := range names {
+		if _, ok := encodingExtensions[Encoding(v)]; ok {
+			encodings = append(encodings, Encoding(v))
+		}
+	}
+	return encodings
+}
+
+// validateEncodings checks every name is a known encoding
+func validateEncodings(names []string) error {
+	for _, v := range names {
+		if _, ok := encodingExtensions[Encoding(v)]; !ok {
+			return errors.Errorf("unknown encoding %s", v)
+		}
+	}
+	return nil
+}
+
+// FileName is the name of the precompressed variant of a file.
+func (e Encoding) FileName(name string) string {
+	return name + encodingExtensions[e]
+}
+
+// VariantNames lists a file along with every precompressed variant it may have.
+func VariantNames(name string) []string {
+	names := []string{name}
+	for encoding := range encodingExtensions {
+		names = append(names, encoding.FileName(name))
+	}
+	return names
+}
+
+// Compress compresses data with the encoding.
+func (e Encoding) Compress(data []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	var writer io.WriteCloser
+	switch e {
+	case EncodingGzip:
+		gzipWriter, err := gzip.NewWriterLevel(&buffer, gzip.BestCompression)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		writer = gzipWriter
+	case EncodingBrotli:
+		writer = brotli.NewWriterLevel(&buffer, brotli.DefaultCompression)
+	default:
+		return nil, errors.Errorf("unknown encoding %s", e)
+	}
+	_, err := writer.Write(data)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	err = writer.Close()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// Decompress decompresses data compressed with the encoding.
+func (e Encoding) Decompress(data []byte) ([]byte, error) {
+	var reader io.Reader
+	switch e {
+	case EncodingGzip:
+		gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	case EncodingBrotli:
+		reader = brotli.NewReader(bytes.NewReader(data))
+	default:
+		return nil, errors.Errorf("unknown encoding %s", e)
+	}
+	decompressed, err := ioutil.ReadAll(reader)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return decompressed, nil
+}
+
+// variantOf splits a file name into the output it holds and the encoding it is precompressed with, if any
+func variantOf(name string) (string, Encoding) {
+	for encoding, extension := range encodingExtensions {
+		if strings.HasSuffix(name, extension) {
+			return strings.TrimSuffix(name, extension), encoding
+		}
+	}
+	return name, ""
+}
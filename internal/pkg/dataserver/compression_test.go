@@ -0,0 +1,97 @@
+package dataserver
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/andybalholm/brotli"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEncodingCompress(t *testing.T) {
+	data := bytes.Repeat([]byte(`{"callsign":"AAL1","altitude":35000}`), 100)
+	tests := []struct {
+		name     string
+		encoding Encoding
+		file     string
+		reader   func(io.Reader) (io.Reader, error)
+	}{
+		{"Gzip", EncodingGzip, "vatsim-data.json.gz", func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) }},
+		{"Brotli", EncodingBrotli, "vatsim-data.json.br", func(r io.Reader) (io.Reader, error) { return brotli.NewReader(r), nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.encoding.FileName("vatsim-data.json"); got != tt.file {
+				t.Errorf("FileName() = %s, want %s", got, tt.file)
+			}
+			compressed, err := tt.encoding.Compress(data)
+			if err != nil {
+				t.Fatalf("Compress() error = %v", err)
+			}
+			if len(compressed) >= len(data) {
+				t.Errorf("Compress() size = %d, want less than %d", len(compressed), len(data))
+			}
+			reader, err := tt.reader(bytes.NewReader(compressed))
+			if err != nil {
+				t.Fatalf("reader error = %v", err)
+			}
+			got, err := ioutil.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("ReadAll() error = %v", err)
+			}
+			if !bytes.Equal(got, data) {
+				t.Errorf("Compress() did not round trip")
+			}
+		})
+	}
+}
+
+func TestFileSinkPrecompressed(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sink")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sink := FileSink{Directory: dir, Encodings: []Encoding{EncodingGzip}}
+	data := []byte(`{"callsign":"AAL1"}`)
+	for _, name := range []string{"vatsim-data.json", "vatsim-data.kmz"} {
+		if err := sink.Write(name, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name     string
+		file     string
+		encoding Encoding
+		written  bool
+	}{
+		{"Written variant", "vatsim-data.json", EncodingGzip, true},
+		{"Variant not written", "vatsim-data.json", EncodingBrotli, false},
+		{"Already compressed", "vatsim-data.kmz", EncodingGzip, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := os.Stat(filepath.Join(dir, tt.encoding.FileName(tt.file)))
+			if written := err == nil; written != tt.written {
+				t.Errorf("Write() wrote %s = %v, want %v", tt.encoding.FileName(tt.file), written, tt.written)
+			}
+			if !Compressible(tt.file) {
+				return
+			}
+			compressed, err := sink.Precompressed(tt.file, data, tt.encoding)
+			if err != nil {
+				t.Fatalf("Precompressed() error = %v", err)
+			}
+			want, err := tt.encoding.Compress(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(compressed, want) {
+				t.Errorf("Precompressed() = %v, want %v", compressed, want)
+			}
+		})
+	}
+}
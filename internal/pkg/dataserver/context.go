@@ -1,13 +1,65 @@
 package dataserver
 
 import (
+	"dataserver/internal/pkg/clock"
+	"dataserver/internal/pkg/config"
+	"dataserver/internal/pkg/fsd"
+	"dataserver/internal/pkg/geo"
+	"dataserver/internal/pkg/logbook"
+	log "github.com/sirupsen/logrus"
 	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
 	"net/textproto"
+	"sync"
 )
 
 // Context holds the application current context
 type Context struct {
+	Config     *config.Config
 	Consumer   *textproto.Conn
+	Writer     *fsd.Writer
 	Producer   *kafka.Producer
 	ClientList *ClientList
+	OptOut     *OptOutList
+	KafkaTypes ClientTypes
+	Logbook    *logbook.Logbook
+	Events     *EventBus
+	Index      *geo.Index
+	Clock      clock.Clock
+	// Deduplicator drops copies of packets already handled, when set.
+	Deduplicator *Deduplicator
+	// Presences are the fake clients connected at startup, which aren't changed on reload.
+	Presences []config.Presence
+	// Mutex guards Config, Producer and KafkaTypes, which are replaced on reload.
+	Mutex *sync.RWMutex
+	// reportedCIDs are the callsigns each CID was last reported connected under, guarded by ClientList.Mutex.
+	reportedCIDs map[int][]string
+}
+
+// Configuration returns the configuration currently in use.
+func (c *Context) Configuration() *config.Config {
+	c.Mutex.RLock()
+	defer c.Mutex.RUnlock()
+	return c.Config
+}
+
+// Reconfigure replaces the configuration in use, along with the Kafka client types and opt-out list read from it.
+func (c *Context) Reconfigure(cfg *config.Config) {
+	c.Mutex.Lock()
+	c.Config = cfg
+	c.KafkaTypes = ClientTypesOf(cfg.Kafka.Types)
+	c.Mutex.Unlock()
+
+	err := c.OptOut.SetPath(cfg.Data.Privacy.OptOut)
+	if err != nil {
+		log.WithField("error", err).Error("Failed to load opt-out list.")
+	}
+}
+
+// ReplaceProducer switches Kafka payloads to another producer, returning the previous one.
+func (c *Context) ReplaceProducer(producer *kafka.Producer) *kafka.Producer {
+	c.Mutex.Lock()
+	defer c.Mutex.Unlock()
+	previous := c.Producer
+	c.Producer = producer
+	return previous
 }
@@ -1,6 +1,8 @@
 package dataserver
 
 import (
+	"context"
+	"dataserver/internal/pkg/clock"
 	"dataserver/internal/pkg/config"
 	"dataserver/internal/pkg/fsd"
 	"encoding/json"
@@ -10,7 +12,6 @@ import (
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/confluentinc/confluent-kafka-go.v1/kafka"
 	"io"
-	"io/ioutil"
 	"time"
 )
 
@@ -30,11 +31,47 @@ var (
 		Help: "The total number of FSD connections.",
 	}, []string{"server"})
 
+	duplicateAddClients = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "dataserver_duplicate_add_clients",
+		Help: "The total number of ADDCLIENT packets for callsigns already connected.",
+	})
+
+	callsignCollisions = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "dataserver_callsign_collisions",
+		Help: "The total number of callsigns seen connected to more than one server.",
+	})
+
+	duplicateCIDs = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "dataserver_duplicate_cids",
+		Help: "The total number of CIDs seen connected under more than one callsign.",
+	})
+
+	streamSubscribers = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "dataserver_stream_subscribers",
+		Help: "The number of clients subscribed to the live stream.",
+	})
+
+	droppedSubscribers = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "dataserver_stream_dropped_subscribers",
+		Help: "The total number of live stream subscribers dropped for falling behind.",
+	})
+
 	packetsProcessed = promauto.NewCounter(prometheus.CounterOpts{
 		Name: "dataserver_packets_processed",
 		Help: "The total number of processed packets.",
 	})
 
+	duplicatePackets = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dataserver_duplicate_packets",
+		Help: "The total number of packets dropped as copies of ones already handled, or ours looped back.",
+	}, []string{"server"})
+
+	packetHops = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "dataserver_packet_hops",
+		Help:    "The hop count of packets received from each server.",
+		Buckets: prometheus.LinearBuckets(0, 1, 10),
+	}, []string{"server"})
+
 	timeToProcessPacket = promauto.NewHistogram(prometheus.HistogramOpts{
 		Name: "dataserver_time_to_process_packet",
 		Help: "The time to process a packet sent by FSD.",
@@ -53,23 +90,33 @@ var (
 	})
 )
 
-// AddFSDClient handles the creation of an FSD client to request data with
-func (c *Context) AddFSDClient() {
-	name, err := config.Cfg.String("data.server.name")
-	if err != nil {
-		log.Fatal("Data server name not defined.")
+// AddFSDClient handles the creation of the FSD clients to request data with
+func (c *Context) AddFSDClient(ctx context.Context) {
+	if len(c.Presences) == 0 {
+		log.Debug("Presences not defined.")
+		return
 	}
 
 	// Initial setup
-	c.sendAddClient(name)
-	time.Sleep(time.Second)
-	c.sendATCData(name)
+	c.sendPresences(ctx)
 
-	// Continually send updates every 30 seconds to keep the connection alive
-	for range time.Tick(30 * time.Second) {
-		c.sendAddClient(name)
-		time.Sleep(time.Second)
-		c.sendATCData(name)
+	// Continually send updates to keep the connections alive
+	c.every(ctx, c.interval(func(t config.Timers) time.Duration { return t.Keepalive }), func() {
+		c.sendPresences(ctx)
+	})
+}
+
+// sendPresences connects every fake client, then sends their positions a second later
+func (c *Context) sendPresences(ctx context.Context) {
+	name := c.Configuration().Data.Server.Name
+	for _, v := range c.Presences {
+		c.sendAddClient(name, v)
+	}
+	if !c.sleep(ctx, time.Second) {
+		return
+	}
+	for _, v := range c.Presences {
+		c.sendATCData(name, v)
 	}
 }
 
@@ -83,64 +130,91 @@ func EncodeJSON(clientList ClientList) ([]byte, error) {
 }
 
 // RemoveTimedOutClients loops through the client lists to find clients who are no longer sending updates, and removes them.
-func (c *Context) RemoveTimedOutClients() {
-	for range time.Tick(5 * time.Minute) {
-		c.checkForTimeouts()
-	}
+func (c *Context) RemoveTimedOutClients(ctx context.Context) {
+	c.every(ctx, c.interval(func(t config.Timers) time.Duration { return t.TimeoutCheck }), c.checkForTimeouts)
 }
 
-// RequestATIS sends requests to all ATC clients for their ATIS every minute
-func (c *Context) RequestATIS() {
-	name, err := config.Cfg.String("data.server.name")
-	if err != nil {
-		log.Fatal("Data server name not defined.")
+// RequestATIS sends requests to all ATC clients for their ATIS from our first fake client, which receives the replies
+func (c *Context) RequestATIS(ctx context.Context) {
+	if len(c.Presences) == 0 {
+		log.Debug("ATIS requests need a presence to receive them.")
+		return
 	}
+	name := c.Configuration().Data.Server.Name
+	from := c.Presences[0].Callsign
 
 	// Initial setup, give the server 5 seconds to process the backlog of added clients
-	time.Sleep(5 * time.Second)
-	c.sendATISRequest(name)
-
-	// Continue to request ATIS data every minute
-	for range time.Tick(time.Minute) {
-		c.sendATISRequest(name)
+	if !c.sleep(ctx, 5*time.Second) {
+		return
 	}
+	c.sendATISRequest(name, from)
+
+	// Continue to request ATIS data
+	c.every(ctx, c.interval(func(t config.Timers) time.Duration { return t.ATIS }), func() {
+		c.sendATISRequest(name, from)
+	})
 }
 
 // SetupServer handles the creation of an FSD server
-func (c *Context) SetupServer() {
+func (c *Context) SetupServer(ctx context.Context) {
 	// Initial setup
 	c.SendNotify()
-	time.Sleep(time.Second)
+	if !c.sleep(ctx, time.Second) {
+		return
+	}
 	c.SendSync()
 
-	// Do this every 2 minutes from now on
-	for range time.Tick(2 * time.Minute) {
+	// Keep announcing ourselves from now on
+	c.every(ctx, c.interval(func(t config.Timers) time.Duration { return t.Notify }), func() {
 		c.SendNotify()
-		time.Sleep(time.Second)
-		c.SendSync()
+		if c.sleep(ctx, time.Second) {
+			c.SendSync()
+		}
+	})
+}
+
+// interval reads a timer from the configuration in use each time, so reloads take effect, and applies the jitter
+func (c *Context) interval(timer func(config.Timers) time.Duration) func() time.Duration {
+	return func() time.Duration {
+		timers := c.Configuration().Timers
+		return clock.Jitter(timer(timers), timers.Jitter)
 	}
 }
 
-// WriteDataFile overwrites the data file with new data.
-func WriteDataFile(clientJSON []byte) error {
-	directory, err := config.Cfg.String("data.file.directory")
-	if err != nil {
-		log.Fatal("Data file directory not defined.")
+// every calls f after every interval until the context is cancelled
+func (c *Context) every(ctx context.Context, interval func() time.Duration, f func()) {
+	for c.sleep(ctx, interval()) {
+		f()
 	}
-	err = ioutil.WriteFile(directory+"vatsim-data.json", clientJSON, 0644)
-	if err != nil {
-		return errors.WithStack(err)
+}
+
+// sleep waits for the duration, returning false if the context is cancelled first
+func (c *Context) sleep(ctx context.Context, duration time.Duration) bool {
+	select {
+	case <-ctx.Done():
+		return false
+	case <-c.Clock.After(duration):
+		return true
 	}
-	return nil
+}
+
+// WriteDataFile overwrites the data file with new data.
+func (s FileSink) WriteDataFile(clientJSON []byte) error {
+	return s.Write(dataFileName, clientJSON)
 }
 
 // checkForTimeouts loops through all clients and checks if they have timed out
 func (c *Context) checkForTimeouts() {
+	timeout := c.Configuration().Timeout
+	now := c.Clock.Now()
+	var ended []Session
+	// Deferred before the unlock, so sessions are recorded once the lock is released
+	defer func() { c.recordSessions(ended) }()
 	c.ClientList.Mutex.Lock()
 	defer c.ClientList.Mutex.Unlock()
 	for i := 0; i < len(c.ClientList.ATCData); i++ {
-		if time.Since(*&c.ClientList.ATCData[i].LastUpdated) >= (5 * time.Minute) {
-			kafkaPush(c.Producer, *&c.ClientList.ATCData[i], "remove_client")
+		if now.Sub(*&c.ClientList.ATCData[i].LastUpdated) >= timeout.Controller {
+			ended = append(ended, c.endSession(c.ClientList.ATCData[i].session(SessionEndTimedOut, now)))
 			totalConnections.With(prometheus.Labels{"server": *&c.ClientList.ATCData[i].Server}).Dec()
 			log.WithFields(log.Fields{
 				"callsign": *&c.ClientList.ATCData[i].Callsign,
@@ -151,8 +225,8 @@ func (c *Context) checkForTimeouts() {
 		}
 	}
 	for i := 0; i < len(c.ClientList.PilotData); i++ {
-		if time.Since(*&c.ClientList.PilotData[i].LastUpdated) >= (5 * time.Minute) {
-			kafkaPush(c.Producer, *&c.ClientList.PilotData[i], "remove_client")
+		if now.Sub(*&c.ClientList.PilotData[i].LastUpdated) >= timeout.Pilot {
+			ended = append(ended, c.endSession(c.ClientList.PilotData[i].session(SessionEndTimedOut, now)))
 			totalConnections.With(prometheus.Labels{"server": *&c.ClientList.PilotData[i].Server}).Dec()
 			log.WithFields(log.Fields{
 				"callsign": *&c.ClientList.PilotData[i].Callsign,
@@ -164,13 +238,20 @@ func (c *Context) checkForTimeouts() {
 	}
 }
 
-// kafkaPush publishes to the Kafka feed
-func kafkaPush(producer *kafka.Producer, data interface{}, messageType string) {
+// kafkaPush publishes to the Kafka feed if Kafka is enabled, stamped with the time it was published
+func (c *Context) kafkaPush(data interface{}, messageType string, timestamp time.Time) {
+	c.Mutex.RLock()
+	producer := c.Producer
+	types := c.KafkaTypes
+	c.Mutex.RUnlock()
+	if producer == nil || !types.IncludesPayload(data) {
+		return
+	}
 	topic := "datafeed"
 	kafkaData := KafkaPayload{
 		MessageType: messageType,
 		Data:        data,
-		Timestamp:   time.Now().UTC(),
+		Timestamp:   timestamp,
 	}
 	jsonData, _ := json.Marshal(kafkaData)
 	err := producer.Produce(&kafka.Message{
@@ -185,18 +266,25 @@ func kafkaPush(producer *kafka.Producer, data interface{}, messageType string) {
 	}
 }
 
-// Listen continually reads, parses and handles FSD packets.
-func (c *Context) Listen() {
+// Listen continually reads, parses and handles FSD packets until the connection is closed or a read fails,
+// as it does once the writer closes the connection after a failed write.
+// Closing the connection after the context is cancelled ends it without an error.
+func (c *Context) Listen(ctx context.Context) error {
 	for {
 		bytes, err := fsd.ReadMessage(c.Consumer)
-		timer := prometheus.NewTimer(timeToProcessPacket)
-		if err == io.EOF {
-			log.Fatal("FSD connection close.")
+		if ctx.Err() != nil {
+			return nil
+		}
+		if errors.Cause(err) == io.EOF {
+			return errors.New("FSD connection closed.")
 		} else if err != nil {
-			log.WithField("error", err).Error("Failed to read message from FSD connection.")
+			return err
 		}
+		timer := prometheus.NewTimer(timeToProcessPacket)
 		fields := fsd.ParseMessage(bytes)
-		c.processMessage(fields)
+		if c.Deduplicator == nil || !c.duplicate(fields) {
+			c.processMessage(fields)
+		}
 		timer.ObserveDuration()
 	}
 }
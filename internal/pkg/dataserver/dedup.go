@@ -0,0 +1,110 @@
+package dataserver
+
+import (
+	"dataserver/internal/pkg/fsd"
+	"sync"
+)
+
+// Deduplicator remembers the packet numbers recently received from each server,
+// so a broadcast reaching us along several paths is only handled once.
+type Deduplicator struct {
+	window  int
+	sources map[string]*packetWindow
+	mutex   sync.Mutex
+}
+
+// restartRun is the number of increasing packets older than the window, or far below the highest,
+// which must arrive in a row before a server is taken to have restarted its numbering
+const restartRun = 3
+
+// packetWindow marks which of the last packet numbers from a server have been received
+type packetWindow struct {
+	highest  int
+	received []bool
+	// the last of the increasing regressed packets received in a row, and how many there were
+	staleLast int
+	staleRun  int
+}
+
+// NewDeduplicator creates a Deduplicator remembering the last window packet numbers from each server.
+func NewDeduplicator(window int) *Deduplicator {
+	return &Deduplicator{
+		window:  window,
+		sources: make(map[string]*packetWindow),
+	}
+}
+
+// Duplicate records a packet, returning whether the same packet from the same server was already received.
+// Packets older than the window are dropped as duplicates, unless enough of them, or of packets far below
+// the highest, arrive in order to show the server restarted its numbering.
+func (d *Deduplicator) Duplicate(base fsd.Base) bool {
+	if base.PacketNumber < 0 {
+		return false
+	}
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	w, ok := d.sources[base.Source]
+	if !ok {
+		w = d.newWindow(base.PacketNumber)
+		d.sources[base.Source] = w
+	}
+	if d.regressed(w, base.PacketNumber) {
+		if d.restarted(w, base.PacketNumber) {
+			w = d.newWindow(base.PacketNumber)
+			d.sources[base.Source] = w
+		} else if base.PacketNumber <= w.highest-d.window {
+			return true
+		}
+	} else {
+		w.staleRun = 0
+	}
+	for n := w.highest + 1; n <= base.PacketNumber && n <= w.highest+d.window; n++ {
+		w.received[n%d.window] = false
+	}
+	if base.PacketNumber > w.highest {
+		w.highest = base.PacketNumber
+	}
+	slot := base.PacketNumber % d.window
+	if w.received[slot] {
+		return true
+	}
+	w.received[slot] = true
+	return false
+}
+
+// newWindow starts remembering packets from a server at a packet number
+func (d *Deduplicator) newWindow(highest int) *packetWindow {
+	return &packetWindow{highest: highest, received: make([]bool, d.window)}
+}
+
+// regressed checks whether a packet is older than the window or less than half the highest,
+// as the first packets after a restart are when the server had sent fewer than the window
+func (d *Deduplicator) regressed(w *packetWindow, number int) bool {
+	return number <= w.highest-d.window || number < w.highest/2
+}
+
+// restarted records a regressed packet, reporting whether it completes a run of them in order
+func (d *Deduplicator) restarted(w *packetWindow, number int) bool {
+	if w.staleRun > 0 && number > w.staleLast && number <= w.staleLast+d.window {
+		w.staleRun++
+	} else {
+		w.staleRun = 1
+	}
+	w.staleLast = number
+	return w.staleRun >= restartRun
+}
+
+// duplicate checks whether a packet is a copy of one already handled or one of ours looped back to us
+func (c *Context) duplicate(fields []string) bool {
+	base, err := fsd.DeserializeBase(fields)
+	if err != nil {
+		// Leave reporting malformed packets to their handlers
+		return false
+	}
+	packetHops.WithLabelValues(base.Source).Observe(float64(base.HopCount))
+	if base.Source == c.Configuration().Data.Server.Name || c.Deduplicator.Duplicate(base) {
+		duplicatePackets.WithLabelValues(base.Source).Inc()
+		return true
+	}
+	return false
+}
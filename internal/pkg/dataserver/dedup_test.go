@@ -0,0 +1,92 @@
+package dataserver
+
+import (
+	"dataserver/internal/pkg/config"
+	"dataserver/internal/pkg/fsd"
+	"testing"
+)
+
+func TestDeduplicatorDuplicate(t *testing.T) {
+	type packet struct {
+		source string
+		number int
+		want   bool
+	}
+	tests := []struct {
+		name    string
+		packets []packet
+	}{
+		{"Copies", []packet{{"SERVER1", 1, false}, {"SERVER1", 2, false}, {"SERVER1", 1, true}, {"SERVER1", 2, true}}},
+		{"Sources", []packet{{"SERVER1", 1, false}, {"SERVER2", 1, false}, {"SERVER2", 1, true}}},
+		{"Out of order", []packet{{"SERVER1", 3, false}, {"SERVER1", 1, false}, {"SERVER1", 2, false}, {"SERVER1", 1, true}}},
+		{"Window slides", []packet{{"SERVER1", 1, false}, {"SERVER1", 5, false}, {"SERVER1", 2, false}, {"SERVER1", 5, true}}},
+		{"Skipped numbers", []packet{{"SERVER1", 3, false}, {"SERVER1", 100, false}, {"SERVER1", 99, false}, {"SERVER1", 99, true}}},
+		{"Stale", []packet{{"SERVER1", 100, false}, {"SERVER1", 1, true}, {"SERVER1", 101, false}}},
+		{"Restart", []packet{{"SERVER1", 100, false}, {"SERVER1", 0, true}, {"SERVER1", 1, true}, {"SERVER1", 3, false}, {"SERVER1", 1, false}, {"SERVER1", 3, true}, {"SERVER1", 4, false}}},
+		{"Stale packets interrupted", []packet{{"SERVER1", 100, false}, {"SERVER1", 0, true}, {"SERVER1", 1, true}, {"SERVER1", 101, false}, {"SERVER1", 2, true}, {"SERVER1", 3, true}, {"SERVER1", 102, false}}},
+		{"Stale packets out of order", []packet{{"SERVER1", 100, false}, {"SERVER1", 5, true}, {"SERVER1", 4, true}, {"SERVER1", 6, true}, {"SERVER1", 101, false}}},
+		{"Negative", []packet{{"SERVER1", -1, false}, {"SERVER1", -1, false}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := NewDeduplicator(4)
+			for i, p := range tt.packets {
+				got := d.Duplicate(fsd.Base{Source: p.source, PacketNumber: p.number})
+				if got != p.want {
+					t.Errorf("Duplicate() of packet %d (%v B%d) = %v, want %v", i, p.source, p.number, got, p.want)
+				}
+			}
+		})
+	}
+}
+
+func TestDeduplicatorRestartWithinWindow(t *testing.T) {
+	tests := []struct {
+		name    string
+		numbers []int
+		want    []bool
+	}{
+		{"Restart", []int{0, 1, 2, 3, 3}, []bool{false, true, false, false, true}},
+		{"Late copies", []int{98, 99, 100}, []bool{true, true, true}},
+		{"Late copies far behind", []int{10, 60, 11, 12}, []bool{true, true, true, true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The server restarts before sending as many packets as the window holds
+			d := NewDeduplicator(1000)
+			for i := 1; i <= 100; i++ {
+				d.Duplicate(fsd.Base{Source: "SERVER1", PacketNumber: i})
+			}
+			for i, v := range tt.numbers {
+				if got := d.Duplicate(fsd.Base{Source: "SERVER1", PacketNumber: v}); got != tt.want[i] {
+					t.Errorf("Duplicate() of packet %d (B%d) = %v, want %v", i, v, got, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestContextDuplicate(t *testing.T) {
+	c := newTestContext(&config.Config{Data: config.Data{Server: config.Server{Name: "DATA"}}}, nil)
+	c.Deduplicator = NewDeduplicator(16)
+	tests := []struct {
+		name   string
+		packet string
+		want   bool
+	}{
+		{"First", "PD:*:SERVER1:B1:1:N:AAL1", false},
+		{"Longer path", "PD:*:SERVER1:B1:3:N:AAL1", true},
+		{"Looped back", "PD:*:DATA:B7:4:N:AAL1", true},
+		{"Multicast", "MC:*P:SERVER1:B2:1:EGLL_TWR:Text", false},
+		{"Multicast copy", "MC:*P:SERVER1:B2:2:EGLL_TWR:Text", true},
+		{"Frequency copy", "MC:@18300:SERVER1:B2:2:EGLL_TWR:Text", true},
+		{"Malformed", "PD:*:SERVER1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := c.duplicate(fsd.ParseMessage(tt.packet)); got != tt.want {
+				t.Errorf("duplicate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
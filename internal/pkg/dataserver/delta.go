@@ -0,0 +1,223 @@
+package dataserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Delta lists the clients added, changed and removed between two serials.
+type Delta struct {
+	From        uint64     `json:"from"`
+	To          uint64     `json:"to"`
+	Pilots      PilotDelta `json:"pilots"`
+	Controllers ATCDelta   `json:"controllers"`
+}
+
+// PilotDelta lists the pilots added, changed and removed between two serials.
+type PilotDelta struct {
+	Added   []Pilot  `json:"added"`
+	Changed []Pilot  `json:"changed"`
+	Removed []string `json:"removed"`
+}
+
+// ATCDelta lists the controllers added, changed and removed between two serials.
+type ATCDelta struct {
+	Added   []ATC    `json:"added"`
+	Changed []ATC    `json:"changed"`
+	Removed []string `json:"removed"`
+}
+
+// Manifest lists the latest serial and the deltas which lead up to it.
+type Manifest struct {
+	Latest   uint64          `json:"latest"`
+	Snapshot string          `json:"snapshot"`
+	Deltas   []ManifestEntry `json:"deltas"`
+}
+
+// ManifestEntry is a single delta file.
+type ManifestEntry struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+	File string `json:"file"`
+}
+
+// DeltaWriter numbers each data file and writes the deltas between them.
+type DeltaWriter struct {
+	Serial   uint64
+	sink     FileSink
+	previous *ClientList
+	deltas   []ManifestEntry
+	stale    []string
+	history  int
+}
+
+// Names of the files written next to the data file.
+const (
+	dataFileName     = "vatsim-data.json"
+	manifestFileName = "vatsim-data-manifest.json"
+	deltaDirectory   = "deltas"
+)
+
+// NewDeltaWriter creates a delta writer keeping the given number of deltas.
+// Serials start at the current Unix time so they keep increasing across restarts.
+// Deltas already in the data directory, such as those written before a restart, are expired first.
+func NewDeltaWriter(sink FileSink, history int) *DeltaWriter {
+	return &DeltaWriter{
+		Serial:  uint64(time.Now().Unix()),
+		sink:    sink,
+		stale:   staleDeltas(sink),
+		history: history,
+	}
+}
+
+// Reconfigure writes to another sink and keeps another number of deltas, continuing the serials.
+// Deltas written to a different directory are no longer listed, and those already in the new one are expired first.
+func (d *DeltaWriter) Reconfigure(sink FileSink, history int) {
+	if sink.Directory != d.sink.Directory {
+		d.deltas = nil
+		d.stale = staleDeltas(sink)
+	}
+	d.sink = sink
+	d.history = history
+}
+
+// staleDeltas lists the deltas already in a data directory oldest first, which are no longer
+// part of the manifest but count towards the history until they expire
+func staleDeltas(sink FileSink) []string {
+	files, err := filepath.Glob(filepath.Join(sink.Directory, deltaDirectory, "*.json"))
+	if err != nil {
+		return nil
+	}
+	serials := map[string]uint64{}
+	var stale []string
+	for _, v := range files {
+		serial, err := strconv.ParseUint(strings.TrimSuffix(filepath.Base(v), ".json"), 10, 64)
+		if err != nil {
+			continue
+		}
+		name := filepath.ToSlash(filepath.Join(deltaDirectory, filepath.Base(v)))
+		serials[name] = serial
+		stale = append(stale, name)
+	}
+	sort.Slice(stale, func(i, j int) bool {
+		return serials[stale[i]] < serials[stale[j]]
+	})
+	return stale
+}
+
+// Next stamps the client list with the next serial.
+func (d *DeltaWriter) Next(clientList ClientList) ClientList {
+	d.Serial++
+	clientList.Serial = d.Serial
+	return clientList
+}
+
+// Write saves the delta from the previous client list and the manifest, returning the files written
+// and the expired deltas removed from the data directory.
+func (d *DeltaWriter) Write(clientList ClientList) ([]string, []string, error) {
+	var files, expired []string
+	if d.previous != nil {
+		delta := ComputeDelta(*d.previous, clientList)
+		name := filepath.ToSlash(filepath.Join(deltaDirectory, fmt.Sprintf("%d.json", delta.To)))
+		deltaJSON, err := json.Marshal(delta)
+		if err != nil {
+			return nil, nil, errors.WithStack(err)
+		}
+		err = d.sink.Write(name, deltaJSON)
+		if err != nil {
+			return nil, nil, err
+		}
+		files = append(files, name)
+		d.deltas = append(d.deltas, ManifestEntry{From: delta.From, To: delta.To, File: name})
+	}
+	d.previous = &clientList
+	for len(d.stale)+len(d.deltas) > d.history {
+		var file string
+		if len(d.stale) > 0 {
+			file = d.stale[0]
+			d.stale = d.stale[1:]
+		} else {
+			file = d.deltas[0].File
+			d.deltas = d.deltas[1:]
+		}
+		d.sink.Remove(file)
+		expired = append(expired, file)
+	}
+
+	manifestJSON, err := json.Marshal(Manifest{
+		Latest:   clientList.Serial,
+		Snapshot: dataFileName,
+		Deltas:   d.deltas,
+	})
+	if err != nil {
+		return nil, nil, errors.WithStack(err)
+	}
+	err = d.sink.Write(manifestFileName, manifestJSON)
+	if err != nil {
+		return nil, nil, err
+	}
+	return append(files, manifestFileName), expired, nil
+}
+
+// ComputeDelta finds the clients added, changed and removed between two client lists.
+func ComputeDelta(previous ClientList, current ClientList) Delta {
+	delta := Delta{
+		From: previous.Serial,
+		To:   current.Serial,
+		Pilots: PilotDelta{
+			Added:   []Pilot{},
+			Changed: []Pilot{},
+			Removed: []string{},
+		},
+		Controllers: ATCDelta{
+			Added:   []ATC{},
+			Changed: []ATC{},
+			Removed: []string{},
+		},
+	}
+
+	pilots := map[string]Pilot{}
+	for _, v := range previous.PilotData {
+		pilots[v.Callsign] = v
+	}
+	for _, v := range current.PilotData {
+		old, found := pilots[v.Callsign]
+		if !found {
+			delta.Pilots.Added = append(delta.Pilots.Added, v)
+		} else if old != v {
+			delta.Pilots.Changed = append(delta.Pilots.Changed, v)
+		}
+		delete(pilots, v.Callsign)
+	}
+	for _, v := range previous.PilotData {
+		if _, removed := pilots[v.Callsign]; removed {
+			delta.Pilots.Removed = append(delta.Pilots.Removed, v.Callsign)
+		}
+	}
+
+	controllers := map[string]ATC{}
+	for _, v := range previous.ATCData {
+		controllers[v.Callsign] = v
+	}
+	for _, v := range current.ATCData {
+		old, found := controllers[v.Callsign]
+		if !found {
+			delta.Controllers.Added = append(delta.Controllers.Added, v)
+		} else if old != v {
+			delta.Controllers.Changed = append(delta.Controllers.Changed, v)
+		}
+		delete(controllers, v.Callsign)
+	}
+	for _, v := range previous.ATCData {
+		if _, removed := controllers[v.Callsign]; removed {
+			delta.Controllers.Removed = append(delta.Controllers.Removed, v.Callsign)
+		}
+	}
+	return delta
+}
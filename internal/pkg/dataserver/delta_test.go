@@ -0,0 +1,153 @@
+package dataserver
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestComputeDelta(t *testing.T) {
+	previous := ClientList{
+		Serial: 1,
+		PilotData: []Pilot{
+			{Callsign: "AAL1", Altitude: 1000},
+			{Callsign: "BAW2", Altitude: 2000},
+			{Callsign: "DAL3", Altitude: 3000},
+		},
+		ATCData: []ATC{
+			{Callsign: "EGLL_TWR", Frequency: 18300},
+		},
+	}
+	current := ClientList{
+		Serial: 2,
+		PilotData: []Pilot{
+			{Callsign: "AAL1", Altitude: 1000},
+			{Callsign: "BAW2", Altitude: 2500},
+			{Callsign: "UAL4", Altitude: 4000},
+		},
+		ATCData: []ATC{
+			{Callsign: "EGLL_TWR", Frequency: 18300},
+			{Callsign: "EGLL_GND", Frequency: 21900},
+		},
+	}
+	delta := ComputeDelta(previous, current)
+	if delta.From != 1 || delta.To != 2 {
+		t.Errorf("ComputeDelta() serials = %d..%d, want 1..2", delta.From, delta.To)
+	}
+	tests := []struct {
+		name string
+		got  []string
+		want []string
+	}{
+		{"Pilots added", pilotCallsigns(delta.Pilots.Added), []string{"UAL4"}},
+		{"Pilots changed", pilotCallsigns(delta.Pilots.Changed), []string{"BAW2"}},
+		{"Pilots removed", delta.Pilots.Removed, []string{"DAL3"}},
+		{"Controllers added", atcCallsigns(delta.Controllers.Added), []string{"EGLL_GND"}},
+		{"Controllers changed", atcCallsigns(delta.Controllers.Changed), []string{}},
+		{"Controllers removed", delta.Controllers.Removed, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if len(tt.got) != len(tt.want) {
+				t.Fatalf("ComputeDelta() got = %v, want %v", tt.got, tt.want)
+			}
+			for i := range tt.got {
+				if tt.got[i] != tt.want[i] {
+					t.Errorf("ComputeDelta() got = %v, want %v", tt.got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func pilotCallsigns(pilots []Pilot) []string {
+	callsigns := []string{}
+	for _, v := range pilots {
+		callsigns = append(callsigns, v.Callsign)
+	}
+	return callsigns
+}
+
+func atcCallsigns(controllers []ATC) []string {
+	callsigns := []string{}
+	for _, v := range controllers {
+		callsigns = append(callsigns, v.Callsign)
+	}
+	return callsigns
+}
+
+func TestDeltaWriterReconfigure(t *testing.T) {
+	directory, err := ioutil.TempDir("", "deltas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	sink := FileSink{Directory: directory}
+	deltas := NewDeltaWriter(sink, 5)
+	for i := 0; i < 4; i++ {
+		if _, _, err := deltas.Write(deltas.Next(ClientList{})); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deltas.Reconfigure(sink, 1)
+	_, expired, err := deltas.Write(deltas.Next(ClientList{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := ioutil.ReadDir(filepath.Join(directory, deltaDirectory))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deltas.deltas) != 1 || len(files) != 1 {
+		t.Errorf("Reconfigure() kept %d deltas and %d files, want 1", len(deltas.deltas), len(files))
+	}
+	if len(expired) != 3 {
+		t.Fatalf("Write() expired %v, want 3 deltas", expired)
+	}
+	for _, v := range expired {
+		if v == deltas.deltas[0].File {
+			t.Errorf("Write() expired the delta it kept, %v", v)
+		}
+		if _, err := os.Stat(filepath.Join(directory, v)); !os.IsNotExist(err) {
+			t.Errorf("Write() expired %v but left it in the data directory", v)
+		}
+	}
+}
+
+func TestDeltaWriterExpiresStaleDeltas(t *testing.T) {
+	directory, err := ioutil.TempDir("", "deltas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	sink := FileSink{Directory: directory, Encodings: []Encoding{EncodingGzip}}
+	// Deltas left by a run before a restart, which sort out of order by name
+	for _, v := range []string{"deltas/99.json", "deltas/100.json", "deltas/101.json"} {
+		if err := sink.Write(v, []byte("{}")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	deltas := NewDeltaWriter(sink, 2)
+	var expired []string
+	for i := 0; i < 3; i++ {
+		_, removed, err := deltas.Write(deltas.Next(ClientList{}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		expired = append(expired, removed...)
+	}
+	want := []string{"deltas/99.json", "deltas/100.json", "deltas/101.json"}
+	if !reflect.DeepEqual(expired, want) {
+		t.Errorf("Write() expired %v, want %v", expired, want)
+	}
+	files, err := ioutil.ReadDir(filepath.Join(directory, deltaDirectory))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Each delta kept is written along with its gzip variant
+	if len(deltas.deltas) != 2 || len(files) != 4 {
+		t.Errorf("Write() kept %d deltas and %d files, want 2 and 4", len(deltas.deltas), len(files))
+	}
+}
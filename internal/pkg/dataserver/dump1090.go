@@ -0,0 +1,75 @@
+package dataserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/pkg/errors"
+	"hash/fnv"
+	"time"
+)
+
+// Dump1090 is the aircraft.json file written by dump1090 and read by ADS-B tools such as tar1090.
+type Dump1090 struct {
+	Now      float64            `json:"now"`
+	Aircraft []Dump1090Aircraft `json:"aircraft"`
+}
+
+// Dump1090Aircraft is a single aircraft in a dump1090 aircraft.json file.
+type Dump1090Aircraft struct {
+	Hex       string  `json:"hex"`
+	Flight    string  `json:"flight"`
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lon"`
+	Altitude  int     `json:"alt_baro"`
+	Speed     int     `json:"gs"`
+	Track     int     `json:"track"`
+	Squawk    string  `json:"squawk"`
+	Seen      float64 `json:"seen"`
+	SeenPos   float64 `json:"seen_pos"`
+}
+
+// ICAOAddress derives a stable pseudo ICAO 24-bit address from a callsign.
+// Real addresses are not known, so the same callsign always maps to the same address instead.
+func ICAOAddress(callsign string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(callsign))
+	return fmt.Sprintf("%06x", hash.Sum32()&0xffffff)
+}
+
+// Squawk formats a transponder code with its leading zeros.
+func Squawk(transponder int) string {
+	return fmt.Sprintf("%04d", transponder)
+}
+
+// EncodeDump1090 encodes the pilots of the client list as a dump1090 aircraft.json file.
+// Pilots who have not sent a position yet are left out.
+func EncodeDump1090(clientList ClientList) ([]byte, error) {
+	now := time.Now().UTC()
+	aircraft := Dump1090{
+		Now:      float64(now.UnixNano()) / float64(time.Second),
+		Aircraft: make([]Dump1090Aircraft, 0, len(clientList.PilotData)),
+	}
+	for _, v := range clientList.PilotData {
+		if v.LastUpdated.IsZero() {
+			continue
+		}
+		seen := now.Sub(v.LastUpdated).Seconds()
+		aircraft.Aircraft = append(aircraft.Aircraft, Dump1090Aircraft{
+			Hex:       ICAOAddress(v.Callsign),
+			Flight:    fmt.Sprintf("%-8s", v.Callsign),
+			Latitude:  v.Latitude,
+			Longitude: v.Longitude,
+			Altitude:  v.Altitude,
+			Speed:     v.Speed,
+			Track:     v.Heading,
+			Squawk:    Squawk(v.Transponder),
+			Seen:      seen,
+			SeenPos:   seen,
+		})
+	}
+	aircraftJSON, err := json.Marshal(aircraft)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return aircraftJSON, nil
+}
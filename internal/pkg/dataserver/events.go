@@ -0,0 +1,214 @@
+package dataserver
+
+import (
+	"dataserver/internal/pkg/geo"
+	"github.com/pkg/errors"
+	"path"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Event is a change to the client list sent to live stream subscribers.
+type Event struct {
+	ID          uint64      `json:"id"`
+	MessageType string      `json:"message_type"`
+	Callsign    string      `json:"callsign,omitempty"`
+	Data        interface{} `json:"data"`
+	Timestamp   time.Time   `json:"timestamp"`
+	Latitude    float64     `json:"-"`
+	Longitude   float64     `json:"-"`
+	Positioned  bool        `json:"-"`
+}
+
+// EventBus keeps a window of recent events and fans new ones out to subscribers.
+type EventBus struct {
+	Mutex       *sync.Mutex
+	history     []Event
+	size        int
+	lastID      uint64
+	subscribers map[chan Event]bool
+}
+
+// EventFilter restricts the events and clients sent to a subscriber.
+type EventFilter struct {
+	Pattern     string
+	BoundingBox *geo.BoundingBox
+	ClientTypes ClientTypes
+}
+
+// NewEventFilter creates a filter from a callsign glob pattern, an optional bounding box and client types.
+func NewEventFilter(pattern string, bbox *geo.BoundingBox, clientTypes ClientTypes) (EventFilter, error) {
+	pattern = strings.ToUpper(pattern)
+	if _, err := path.Match(pattern, ""); err != nil {
+		return EventFilter{}, errors.New("Filter callsign is not a valid pattern.")
+	}
+	return EventFilter{
+		Pattern:     pattern,
+		BoundingBox: bbox,
+		ClientTypes: clientTypes,
+	}, nil
+}
+
+// Matches checks if an event passes the filter.
+func (f EventFilter) Matches(event Event) bool {
+	return f.matchesClient(event.Callsign, event.Positioned, event.Latitude, event.Longitude) &&
+		f.ClientTypes.IncludesPayload(event.Data)
+}
+
+// FilterClientList returns a copy of the client list with only the clients passing the filter.
+func (f EventFilter) FilterClientList(clientList ClientList) ClientList {
+	clientList = clientList.FilterClientTypes(f.ClientTypes)
+	filtered := ClientList{
+		PilotData: []Pilot{},
+		ATCData:   []ATC{},
+		Mutex:     clientList.Mutex,
+	}
+	for _, v := range clientList.PilotData {
+		if f.matchesClient(v.Callsign, !v.LastUpdated.IsZero(), v.Latitude, v.Longitude) {
+			filtered.PilotData = append(filtered.PilotData, v)
+		}
+	}
+	for _, v := range clientList.ATCData {
+		if f.matchesClient(v.Callsign, !v.LastUpdated.IsZero(), v.Latitude, v.Longitude) {
+			filtered.ATCData = append(filtered.ATCData, v)
+		}
+	}
+	return filtered
+}
+
+// matchesClient checks a client's callsign and position against the filter
+func (f EventFilter) matchesClient(callsign string, positioned bool, latitude float64, longitude float64) bool {
+	if f.Pattern != "" {
+		if ok, _ := path.Match(f.Pattern, strings.ToUpper(callsign)); !ok {
+			return false
+		}
+	}
+	if f.BoundingBox != nil && (!positioned || !f.BoundingBox.Contains(geo.Point{Latitude: latitude, Longitude: longitude})) {
+		return false
+	}
+	return true
+}
+
+// subscriberBuffer is how far a subscriber may fall behind before it is dropped.
+const subscriberBuffer = 256
+
+// NewEventBus creates an event bus remembering the given number of events for resuming subscribers.
+func NewEventBus(size int) *EventBus {
+	return &EventBus{
+		Mutex:       &sync.Mutex{},
+		size:        size,
+		subscribers: map[chan Event]bool{},
+	}
+}
+
+// Publish numbers an event and sends it to every subscriber, dropping those which cannot keep up.
+func (b *EventBus) Publish(event Event) {
+	if b == nil {
+		return
+	}
+	b.Mutex.Lock()
+	defer b.Mutex.Unlock()
+	b.lastID++
+	event.ID = b.lastID
+	b.history = append(b.history, event)
+	if len(b.history) > b.size {
+		b.history = b.history[len(b.history)-b.size:]
+	}
+	for subscriber := range b.subscribers {
+		select {
+		case subscriber <- event:
+		default:
+			delete(b.subscribers, subscriber)
+			close(subscriber)
+			droppedSubscribers.Inc()
+		}
+	}
+}
+
+// Cursor returns the ID of the last event published.
+func (b *EventBus) Cursor() uint64 {
+	if b == nil {
+		return 0
+	}
+	b.Mutex.Lock()
+	defer b.Mutex.Unlock()
+	return b.lastID
+}
+
+// Subscribe returns the events after the cursor followed by a channel of new events.
+// A cursor of zero starts from now. It reports false if the cursor is no longer in the history.
+func (b *EventBus) Subscribe(cursor uint64) ([]Event, chan Event, bool) {
+	return b.subscribe(cursor, cursor == 0)
+}
+
+// SubscribeAfter returns every event after the cursor followed by a channel of new events,
+// where a cursor of zero is before the first event. It reports false if the cursor is no longer in the history.
+func (b *EventBus) SubscribeAfter(cursor uint64) ([]Event, chan Event, bool) {
+	return b.subscribe(cursor, false)
+}
+
+// subscribe adds a subscriber, with the backlog after the cursor unless it starts from now
+func (b *EventBus) subscribe(cursor uint64, fromNow bool) ([]Event, chan Event, bool) {
+	b.Mutex.Lock()
+	defer b.Mutex.Unlock()
+	var backlog []Event
+	if cursor > b.lastID {
+		return nil, nil, false
+	}
+	if !fromNow && cursor < b.lastID {
+		if len(b.history) == 0 || b.history[0].ID > cursor+1 {
+			return nil, nil, false
+		}
+		for _, v := range b.history {
+			if v.ID > cursor {
+				backlog = append(backlog, v)
+			}
+		}
+	}
+	subscriber := make(chan Event, subscriberBuffer)
+	b.subscribers[subscriber] = true
+	streamSubscribers.Inc()
+	return backlog, subscriber, true
+}
+
+// Unsubscribe stops sending events to a subscriber.
+func (b *EventBus) Unsubscribe(subscriber chan Event) {
+	b.Mutex.Lock()
+	defer b.Mutex.Unlock()
+	streamSubscribers.Dec()
+	if b.subscribers[subscriber] {
+		delete(b.subscribers, subscriber)
+		close(subscriber)
+	}
+}
+
+// subject describes which client an event is about so subscribers can filter it
+func subject(data interface{}) Event {
+	switch v := data.(type) {
+	case Pilot:
+		return Event{Callsign: v.Callsign, Latitude: v.Latitude, Longitude: v.Longitude, Positioned: !v.LastUpdated.IsZero()}
+	case ATC:
+		return Event{Callsign: v.Callsign, Latitude: v.Latitude, Longitude: v.Longitude, Positioned: !v.LastUpdated.IsZero()}
+	case Session:
+		return Event{Callsign: v.Callsign, Latitude: v.Latitude, Longitude: v.Longitude, Positioned: v.Latitude != 0 || v.Longitude != 0}
+	case Anomaly:
+		return Event{Callsign: v.Callsign}
+	}
+	return Event{}
+}
+
+// publish sends a change to Kafka and the live stream
+func (c *Context) publish(data interface{}, messageType string) {
+	c.publishAbout(subject(data), data, messageType)
+}
+
+// publishAbout sends a change about a client to Kafka and the live stream
+func (c *Context) publishAbout(event Event, data interface{}, messageType string) {
+	data = c.OptOut.redact(data)
+	event.MessageType = messageType
+	event.Data = data
+	event.Timestamp = c.Clock.Now().UTC()
+	c.Events.Publish(event)
+	c.kafkaPush(data, messageType, event.Timestamp)
+}
@@ -0,0 +1,49 @@
+package dataserver
+
+import (
+	"testing"
+)
+
+func TestEventBusSubscribe(t *testing.T) {
+	bus := NewEventBus(3)
+	for i := 0; i < 5; i++ {
+		bus.Publish(Event{MessageType: "update_position"})
+	}
+	tests := []struct {
+		name   string
+		cursor uint64
+		want   []uint64
+		wantOk bool
+	}{
+		{"From now", 0, nil, true},
+		{"Up to date", 5, nil, true},
+		{"Resume", 3, []uint64{4, 5}, true},
+		{"Oldest in history", 2, []uint64{3, 4, 5}, true},
+		{"Expired", 1, nil, false},
+		{"Unknown", 6, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backlog, events, ok := bus.Subscribe(tt.cursor)
+			if ok != tt.wantOk {
+				t.Fatalf("Subscribe() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if !ok {
+				return
+			}
+			defer bus.Unsubscribe(events)
+			var got []uint64
+			for _, v := range backlog {
+				got = append(got, v.ID)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Subscribe() got = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Subscribe() got = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package dataserver
+
+import (
+	"dataserver/internal/pkg/config"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+// FileSink writes output files and their precompressed variants to the data directory.
+type FileSink struct {
+	Directory string
+	Encodings []Encoding
+}
+
+// NewFileSink creates the sink for the configured data directory.
+func NewFileSink(cfg config.File) FileSink {
+	return FileSink{
+		Directory: cfg.Directory,
+		Encodings: EncodingsOf(cfg.Compression),
+	}
+}
+
+// Write overwrites a file in the data directory along with its precompressed variants if it is compressible,
+// creating its parent directory if needed.
+func (s FileSink) Write(name string, data []byte) error {
+	path := filepath.Join(s.Directory, name)
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	err = writeFile(path, data)
+	if err != nil {
+		return err
+	}
+	if !Compressible(name) {
+		return nil
+	}
+	for _, encoding := range s.Encodings {
+		compressed, err := encoding.Compress(data)
+		if err != nil {
+			return err
+		}
+		err = writeFile(encoding.FileName(path), compressed)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFile replaces a file by renaming a temporary file over it, so readers never see it half written
+func writeFile(path string, data []byte) error {
+	file, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = file.Write(data)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(file.Name(), 0644)
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		os.Remove(file.Name())
+		return errors.WithStack(err)
+	}
+	return nil
+}
+
+// Precompressed finds the variant of a file the sink wrote with the encoding,
+// compressing the data only if the sink does not write that variant itself.
+func (s FileSink) Precompressed(name string, data []byte, encoding Encoding) ([]byte, error) {
+	for _, v := range s.Encodings {
+		if v == encoding {
+			compressed, err := ioutil.ReadFile(encoding.FileName(filepath.Join(s.Directory, name)))
+			return compressed, errors.WithStack(err)
+		}
+	}
+	return encoding.Compress(data)
+}
+
+// Remove deletes a file from the data directory along with any precompressed variants.
+func (s FileSink) Remove(name string) {
+	for _, v := range VariantNames(filepath.Join(s.Directory, name)) {
+		err := os.Remove(v)
+		if err != nil && !os.IsNotExist(err) {
+			log.WithFields(log.Fields{
+				"file":  v,
+				"error": err,
+			}).Error("Failed to remove data file.")
+		}
+	}
+}
@@ -56,7 +56,7 @@ func (c *Context) HandleFlightPlan(fields []string) error {
 					MinutesFuel:    flightPlan.MinutesFuel,
 				},
 			}
-			kafkaPush(c.Producer, c.ClientList.PilotData[i].FlightPlan, "update_flight_plan")
+			c.publishAbout(subject(c.ClientList.PilotData[i]), c.ClientList.PilotData[i].FlightPlan, "update_flight_plan")
 			break
 		}
 	}
@@ -0,0 +1,126 @@
+package dataserver
+
+import (
+	"github.com/pkg/errors"
+	"path/filepath"
+)
+
+// Format is an additional encoding of the client list written next to the data file.
+type Format string
+
+// Formats which can be written next to the data file.
+const (
+	FormatGeoJSON     Format = "geojson"
+	FormatKML         Format = "kml"
+	FormatKMZ         Format = "kmz"
+	FormatDump1090    Format = "dump1090"
+	FormatProtobuf    Format = "protobuf"
+	FormatMessagePack Format = "msgpack"
+)
+
+// NetworkLinkFileName is the KML file which refreshes the KML or KMZ output.
+const NetworkLinkFileName = "vatsim-data-link.kml"
+
+// formatEncoder describes the file a format is written to
+type formatEncoder struct {
+	fileName string
+	encode   func(ClientList, *Tracks) ([]byte, error)
+}
+
+// formatEncoders are the encoders of each format
+var formatEncoders = map[Format]formatEncoder{
+	FormatGeoJSON:     {"vatsim-data.geojson", withoutTracks(EncodeGeoJSON)},
+	FormatKML:         {"vatsim-data.kml", EncodeKML},
+	FormatKMZ:         {"vatsim-data.kmz", EncodeKMZ},
+	FormatDump1090:    {"aircraft.json", withoutTracks(EncodeDump1090)},
+	FormatMessagePack: {"vatsim-data.msgpack", withoutTracks(EncodeMessagePack)},
+}
+
+// contentTypes are the media types of each output file extension
+var contentTypes = map[string]string{
+	".json":    "application/json",
+	".geojson": "application/geo+json",
+	".kml":     "application/vnd.google-earth.kml+xml",
+	".kmz":     "application/vnd.google-earth.kmz",
+	".pb":      "application/x-protobuf",
+	".msgpack": "application/msgpack",
+}
+
+// RegisterFormat adds a format whose encoder lives outside this package.
+func RegisterFormat(format Format, fileName string, encode func(ClientList) ([]byte, error)) {
+	formatEncoders[format] = formatEncoder{fileName, withoutTracks(encode)}
+}
+
+// FormatOf finds the format a file was written in.
+func FormatOf(fileName string) (Format, bool) {
+	for k, v := range formatEncoders {
+		if v.fileName == fileName {
+			return k, true
+		}
+	}
+	return "", false
+}
+
+// FormatsOf converts the format names of an output, skipping unknown names.
+// The formats are nil only if the names are.
+func FormatsOf(names []string) []Format {
+	if names == nil {
+		return nil
+	}
+	formats := []Format{}
+	for _, v := range names {
+		if _, ok := formatEncoders[Format(v)]; ok {
+			formats = append(formats, Format(v))
+		}
+	}
+	return formats
+}
+
+// validateFormats checks every name is a known format
+func validateFormats(names []string) error {
+	for _, v := range names {
+		if _, ok := formatEncoders[Format(v)]; !ok {
+			return errors.Errorf("unknown format %s", v)
+		}
+	}
+	return nil
+}
+
+// FileName is the name of the file the format is written to.
+func (f Format) FileName() string {
+	return formatEncoders[f].fileName
+}
+
+// Write encodes the client list in the format and saves it to the data directory.
+func (f Format) Write(sink FileSink, clientList ClientList, tracks *Tracks) error {
+	data, err := formatEncoders[f].encode(clientList, tracks)
+	if err != nil {
+		return err
+	}
+	return sink.Write(f.FileName(), data)
+}
+
+// withoutTracks adapts an encoder which has no use for recorded tracks
+func withoutTracks(encode func(ClientList) ([]byte, error)) func(ClientList, *Tracks) ([]byte, error) {
+	return func(clientList ClientList, _ *Tracks) ([]byte, error) {
+		return encode(clientList)
+	}
+}
+
+// ContentType finds the media type of an output file by its extension.
+func ContentType(name string) string {
+	contentType, ok := contentTypes[filepath.Ext(name)]
+	if !ok {
+		return "application/octet-stream"
+	}
+	return contentType
+}
+
+// WriteNetworkLink saves a KML file which makes Google Earth reload a KML or KMZ file every interval seconds.
+func (s FileSink) WriteNetworkLink(href string, interval int) error {
+	data, err := EncodeNetworkLink(href, interval)
+	if err != nil {
+		return err
+	}
+	return s.Write(NetworkLinkFileName, data)
+}
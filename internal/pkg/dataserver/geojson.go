@@ -0,0 +1,160 @@
+package dataserver
+
+import (
+	"dataserver/internal/pkg/geo"
+	"encoding/json"
+	"github.com/pkg/errors"
+)
+
+// coverageSegments is the number of sides of the polygon approximating a controller's coverage.
+const coverageSegments = 64
+
+// FeatureCollection is a GeoJSON collection of features.
+type FeatureCollection struct {
+	Type     string    `json:"type"`
+	Features []Feature `json:"features"`
+}
+
+// Feature is a GeoJSON feature.
+type Feature struct {
+	Type       string      `json:"type"`
+	ID         string      `json:"id"`
+	Geometry   Geometry    `json:"geometry"`
+	Properties interface{} `json:"properties"`
+}
+
+// Geometry is a GeoJSON point or polygon.
+type Geometry struct {
+	Type        string      `json:"type"`
+	Coordinates interface{} `json:"coordinates"`
+}
+
+// PilotProperties describes a pilot feature.
+type PilotProperties struct {
+	Kind       string            `json:"kind"`
+	Callsign   string            `json:"callsign"`
+	CID        int               `json:"cid"`
+	Name       string            `json:"name"`
+	Server     string            `json:"server"`
+	Altitude   int               `json:"altitude"`
+	Speed      int               `json:"speed"`
+	Heading    int               `json:"heading"`
+	FlightPlan FlightPlanSummary `json:"plan"`
+}
+
+// FlightPlanSummary is the part of a flight plan shown on a map.
+type FlightPlanSummary struct {
+	FlightRules string `json:"flight_rules"`
+	Aircraft    string `json:"aircraft"`
+	Departure   string `json:"departure"`
+	Arrival     string `json:"arrival"`
+	Altitude    string `json:"altitude"`
+}
+
+// ATCProperties describes a controller position or coverage feature.
+type ATCProperties struct {
+	Kind       string     `json:"kind"`
+	Callsign   string     `json:"callsign"`
+	ClientType ClientType `json:"type"`
+	CID        int        `json:"cid"`
+	Name       string     `json:"name"`
+	Server     string     `json:"server"`
+	Frequency  int        `json:"frequency"`
+	Facility   int        `json:"facility"`
+	Range      int        `json:"range"`
+}
+
+// EncodeGeoJSON encodes the client list as a GeoJSON feature collection.
+// Pilots are points, controllers are points plus a polygon approximating their visual range.
+// Clients which have not sent a position yet are left out.
+func EncodeGeoJSON(clientList ClientList) ([]byte, error) {
+	collection := FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: []Feature{},
+	}
+	for _, v := range clientList.PilotData {
+		if v.LastUpdated.IsZero() {
+			continue
+		}
+		collection.Features = append(collection.Features, Feature{
+			Type:     "Feature",
+			ID:       v.Callsign,
+			Geometry: pointGeometry(geo.Point{Latitude: v.Latitude, Longitude: v.Longitude}),
+			Properties: PilotProperties{
+				Kind:     "pilot",
+				Callsign: v.Callsign,
+				CID:      v.Member.CID,
+				Name:     v.Member.Name,
+				Server:   v.Server,
+				Altitude: v.Altitude,
+				Speed:    v.Speed,
+				Heading:  v.Heading,
+				FlightPlan: FlightPlanSummary{
+					FlightRules: v.FlightPlan.FlightRules,
+					Aircraft:    v.FlightPlan.Aircraft,
+					Departure:   v.FlightPlan.Departure,
+					Arrival:     v.FlightPlan.Arrival,
+					Altitude:    v.FlightPlan.Altitude,
+				},
+			},
+		})
+	}
+	for _, v := range clientList.ATCData {
+		if v.LastUpdated.IsZero() {
+			continue
+		}
+		center := geo.Point{Latitude: v.Latitude, Longitude: v.Longitude}
+		properties := ATCProperties{
+			Kind:       "controller",
+			Callsign:   v.Callsign,
+			ClientType: v.ClientType,
+			CID:        v.Member.CID,
+			Name:       v.Member.Name,
+			Server:     v.Server,
+			Frequency:  v.Frequency,
+			Facility:   v.FacilityType,
+			Range:      v.VisualRange,
+		}
+		collection.Features = append(collection.Features, Feature{
+			Type:       "Feature",
+			ID:         v.Callsign,
+			Geometry:   pointGeometry(center),
+			Properties: properties,
+		})
+		if v.VisualRange <= 0 {
+			continue
+		}
+		properties.Kind = "coverage"
+		collection.Features = append(collection.Features, Feature{
+			Type:       "Feature",
+			ID:         v.Callsign + "#coverage",
+			Geometry:   polygonGeometry(geo.Circle(center, float64(v.VisualRange), coverageSegments)),
+			Properties: properties,
+		})
+	}
+	geoJSON, err := json.Marshal(collection)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return geoJSON, nil
+}
+
+// pointGeometry converts a point to longitude, latitude order
+func pointGeometry(p geo.Point) Geometry {
+	return Geometry{
+		Type:        "Point",
+		Coordinates: [2]float64{p.Longitude, p.Latitude},
+	}
+}
+
+// polygonGeometry converts a closed ring to a polygon without holes
+func polygonGeometry(ring []geo.Point) Geometry {
+	coordinates := make([][2]float64, 0, len(ring))
+	for _, v := range ring {
+		coordinates = append(coordinates, [2]float64{v.Longitude, v.Latitude})
+	}
+	return Geometry{
+		Type:        "Polygon",
+		Coordinates: [][][2]float64{coordinates},
+	}
+}
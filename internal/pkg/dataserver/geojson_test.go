@@ -0,0 +1,72 @@
+package dataserver
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestEncodeGeoJSON(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clientList := ClientList{
+		PilotData: []Pilot{
+			{Callsign: "AAL1", Latitude: 40.64, Longitude: -73.78, LastUpdated: now},
+			{Callsign: "BAW2"},
+		},
+		ATCData: []ATC{
+			{Callsign: "EGLL_TWR", Latitude: 51.47, Longitude: -0.46, VisualRange: 50, LastUpdated: now},
+			{Callsign: "EGLL_GND", VisualRange: 50},
+		},
+	}
+	geoJSON, err := EncodeGeoJSON(clientList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var collection struct {
+		Features []struct {
+			ID       string `json:"id"`
+			Geometry struct {
+				Type        string          `json:"type"`
+				Coordinates json.RawMessage `json:"coordinates"`
+			} `json:"geometry"`
+		} `json:"features"`
+	}
+	if err := json.Unmarshal(geoJSON, &collection); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"AAL1", "EGLL_TWR", "EGLL_TWR#coverage"}
+	if len(collection.Features) != len(want) {
+		t.Fatalf("EncodeGeoJSON() wrote %d features, want %v", len(collection.Features), want)
+	}
+	for i, v := range collection.Features {
+		if v.ID != want[i] {
+			t.Errorf("EncodeGeoJSON() feature %d = %s, want %s", i, v.ID, want[i])
+		}
+	}
+
+	var point [2]float64
+	if err := json.Unmarshal(collection.Features[0].Geometry.Coordinates, &point); err != nil {
+		t.Fatal(err)
+	}
+	if point != [2]float64{-73.78, 40.64} {
+		t.Errorf("EncodeGeoJSON() point = %v, want longitude then latitude", point)
+	}
+
+	var polygon [][][2]float64
+	if err := json.Unmarshal(collection.Features[2].Geometry.Coordinates, &polygon); err != nil {
+		t.Fatal(err)
+	}
+	ring := polygon[0]
+	if len(ring) != coverageSegments+1 || ring[0] != ring[len(ring)-1] {
+		t.Fatalf("EncodeGeoJSON() coverage has %d points, want a closed ring of %d", len(ring), coverageSegments+1)
+	}
+	// The shoelace sum is positive for a counter-clockwise exterior ring
+	area := 0.0
+	for i := 0; i < len(ring)-1; i++ {
+		area += ring[i][0]*ring[i+1][1] - ring[i+1][0]*ring[i][1]
+	}
+	if area <= 0 {
+		t.Errorf("EncodeGeoJSON() coverage ring is clockwise")
+	}
+}
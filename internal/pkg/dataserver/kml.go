@@ -0,0 +1,217 @@
+package dataserver
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"github.com/pkg/errors"
+	"strings"
+)
+
+// Icons used for placemarks.
+const (
+	aircraftIcon   = "http://maps.google.com/mapfiles/kml/shapes/airports.png"
+	controllerIcon = "http://maps.google.com/mapfiles/kml/shapes/radio.png"
+	feetToMetres   = 0.3048
+)
+
+// kmlDocument is the root of a KML file
+type kmlDocument struct {
+	XMLName  xml.Name     `xml:"kml"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Document *kmlFolder   `xml:"Document,omitempty"`
+	Link     *networkLink `xml:"NetworkLink,omitempty"`
+}
+
+// kmlFolder groups placemarks and other folders
+type kmlFolder struct {
+	Name       string         `xml:"name"`
+	Styles     []kmlStyle     `xml:"Style,omitempty"`
+	Folders    []kmlFolder    `xml:"Folder,omitempty"`
+	Placemarks []kmlPlacemark `xml:"Placemark,omitempty"`
+}
+
+// kmlStyle sets the icon and line of a placemark
+type kmlStyle struct {
+	ID        string        `xml:"id,attr,omitempty"`
+	IconStyle *kmlIconStyle `xml:"IconStyle,omitempty"`
+	LineStyle *kmlLineStyle `xml:"LineStyle,omitempty"`
+}
+
+// kmlIconStyle is the icon of a point, rotated by heading
+type kmlIconStyle struct {
+	Heading int    `xml:"heading"`
+	Href    string `xml:"Icon>href"`
+}
+
+// kmlLineStyle is the colour and width of a line
+type kmlLineStyle struct {
+	Color string `xml:"color"`
+	Width int    `xml:"width"`
+}
+
+// kmlPlacemark is a single pilot, controller or track
+type kmlPlacemark struct {
+	ID          string         `xml:"id,attr,omitempty"`
+	Name        string         `xml:"name"`
+	Description string         `xml:"description,omitempty"`
+	StyleURL    string         `xml:"styleUrl,omitempty"`
+	Style       *kmlStyle      `xml:"Style,omitempty"`
+	Point       *kmlPoint      `xml:"Point,omitempty"`
+	LineString  *kmlLineString `xml:"LineString,omitempty"`
+}
+
+// kmlPoint is a position, optionally above the ground
+type kmlPoint struct {
+	Extrude      int    `xml:"extrude,omitempty"`
+	AltitudeMode string `xml:"altitudeMode"`
+	Coordinates  string `xml:"coordinates"`
+}
+
+// kmlLineString is a path through several positions
+type kmlLineString struct {
+	Tessellate   int    `xml:"tessellate"`
+	AltitudeMode string `xml:"altitudeMode"`
+	Coordinates  string `xml:"coordinates"`
+}
+
+// networkLink points Google Earth at a file to refresh periodically
+type networkLink struct {
+	Name            string `xml:"name"`
+	Href            string `xml:"Link>href"`
+	RefreshMode     string `xml:"Link>refreshMode"`
+	RefreshInterval int    `xml:"Link>refreshInterval"`
+}
+
+// EncodeKML encodes the client list as a KML document with aircraft at their altitude,
+// controller positions and the recorded track of each pilot.
+// Clients which have not sent a position yet are left out.
+func EncodeKML(clientList ClientList, tracks *Tracks) ([]byte, error) {
+	pilots := kmlFolder{Name: "Pilots"}
+	trackLines := kmlFolder{Name: "Tracks"}
+	for _, v := range clientList.PilotData {
+		if v.LastUpdated.IsZero() {
+			continue
+		}
+		pilots.Placemarks = append(pilots.Placemarks, kmlPlacemark{
+			ID:          v.Callsign,
+			Name:        v.Callsign,
+			Description: pilotDescription(v),
+			Style: &kmlStyle{
+				IconStyle: &kmlIconStyle{Heading: v.Heading, Href: aircraftIcon},
+			},
+			Point: &kmlPoint{
+				Extrude:      1,
+				AltitudeMode: "absolute",
+				Coordinates:  kmlCoordinate(v.Latitude, v.Longitude, v.Altitude),
+			},
+		})
+		track := tracks.Track(v.Callsign)
+		if len(track) < 2 {
+			continue
+		}
+		coordinates := make([]string, 0, len(track))
+		for _, p := range track {
+			coordinates = append(coordinates, kmlCoordinate(p.Latitude, p.Longitude, p.Altitude))
+		}
+		trackLines.Placemarks = append(trackLines.Placemarks, kmlPlacemark{
+			Name:     v.Callsign,
+			StyleURL: "#track",
+			LineString: &kmlLineString{
+				Tessellate:   1,
+				AltitudeMode: "absolute",
+				Coordinates:  strings.Join(coordinates, " "),
+			},
+		})
+	}
+	controllers := kmlFolder{Name: "Controllers"}
+	for _, v := range clientList.ATCData {
+		if v.LastUpdated.IsZero() {
+			continue
+		}
+		controllers.Placemarks = append(controllers.Placemarks, kmlPlacemark{
+			ID:          v.Callsign,
+			Name:        v.Callsign,
+			Description: fmt.Sprintf("%s\n%s", formatFrequency(v.Frequency), v.Member.Name),
+			StyleURL:    "#controller",
+			Point: &kmlPoint{
+				AltitudeMode: "clampToGround",
+				Coordinates:  kmlCoordinate(v.Latitude, v.Longitude, 0),
+			},
+		})
+	}
+
+	document := kmlFolder{
+		Name: "VATSIM",
+		Styles: []kmlStyle{
+			{ID: "controller", IconStyle: &kmlIconStyle{Href: controllerIcon}},
+			{ID: "track", LineStyle: &kmlLineStyle{Color: "ff00ffff", Width: 2}},
+		},
+		Folders: []kmlFolder{pilots, controllers},
+	}
+	if tracks != nil {
+		document.Folders = append(document.Folders, trackLines)
+	}
+	return marshalKML(kmlDocument{Document: &document})
+}
+
+// EncodeKMZ encodes the client list as KML compressed into a KMZ archive.
+func EncodeKMZ(clientList ClientList, tracks *Tracks) ([]byte, error) {
+	kml, err := EncodeKML(clientList, tracks)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	archive := zip.NewWriter(&buffer)
+	file, err := archive.Create("doc.kml")
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	_, err = file.Write(kml)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	err = archive.Close()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return buffer.Bytes(), nil
+}
+
+// EncodeNetworkLink encodes a KML file which makes Google Earth reload a KML or KMZ file every interval seconds.
+func EncodeNetworkLink(href string, interval int) ([]byte, error) {
+	return marshalKML(kmlDocument{
+		Link: &networkLink{
+			Name:            "VATSIM",
+			Href:            href,
+			RefreshMode:     "onInterval",
+			RefreshInterval: interval,
+		},
+	})
+}
+
+// marshalKML adds the XML header and namespace to a document
+func marshalKML(document kmlDocument) ([]byte, error) {
+	document.XMLNS = "http://www.opengis.net/kml/2.2"
+	kml, err := xml.Marshal(document)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return append([]byte(xml.Header), kml...), nil
+}
+
+// kmlCoordinate formats a position with its altitude in feet as longitude,latitude,metres
+func kmlCoordinate(latitude float64, longitude float64, altitude int) string {
+	return fmt.Sprintf("%f,%f,%.0f", longitude, latitude, float64(altitude)*feetToMetres)
+}
+
+// pilotDescription summarises a pilot's flight plan and state
+func pilotDescription(p Pilot) string {
+	return fmt.Sprintf("%s %s-%s\n%d ft %d kt\n%s", p.FlightPlan.Aircraft, p.FlightPlan.Departure, p.FlightPlan.Arrival, p.Altitude, p.Speed, p.Member.Name)
+}
+
+// formatFrequency formats an FSD frequency such as 18300 as 118.300
+func formatFrequency(frequency int) string {
+	return fmt.Sprintf("1%02d.%03d", frequency/1000, frequency%1000)
+}
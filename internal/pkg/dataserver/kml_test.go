@@ -0,0 +1,35 @@
+package dataserver
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncodeKML(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clientList := ClientList{
+		PilotData: []Pilot{
+			{Callsign: "AAL1", Latitude: 40.64, Longitude: -73.78, LastUpdated: now},
+			{Callsign: "BAW2"},
+		},
+		ATCData: []ATC{
+			{Callsign: "EGLL_TWR", Latitude: 51.47, Longitude: -0.46, LastUpdated: now},
+			{Callsign: "EGLL_GND"},
+		},
+	}
+	kml, err := EncodeKML(clientList, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []string{"AAL1", "EGLL_TWR"} {
+		if !strings.Contains(string(kml), v) {
+			t.Errorf("EncodeKML() left out %s", v)
+		}
+	}
+	for _, v := range []string{"BAW2", "EGLL_GND"} {
+		if strings.Contains(string(kml), v) {
+			t.Errorf("EncodeKML() placed %s before a position was sent", v)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package dataserver
+
+import (
+	"context"
+	"dataserver/internal/pkg/clock"
+	"dataserver/internal/pkg/config"
+	"dataserver/internal/pkg/fsd"
+	"net"
+	"net/textproto"
+	"testing"
+	"time"
+)
+
+func TestListenEndsAfterFailedWrite(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	c := newTestContext(&config.Config{}, clock.NewFake(time.Now()))
+	c.Consumer = textproto.NewConn(client)
+	c.Writer = fsd.NewWriter(client, fsd.WriterOptions{Queue: 10, Timeout: 10 * time.Millisecond})
+	listened := make(chan error, 1)
+	go func() {
+		listened <- c.Listen(context.Background())
+	}()
+
+	// Nothing reads the pipe, so the write times out and the writer closes the connection
+	if err := c.Writer.Send(&fsd.Sync{Base: fsd.Base{Destination: "*", Source: "DATA"}}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-listened:
+		if err == nil {
+			t.Error("Listen() returned no error, want the failed read")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Listen() kept reading from the closed connection")
+	}
+}
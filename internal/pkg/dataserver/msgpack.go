@@ -0,0 +1,18 @@
+package dataserver
+
+import (
+	"bytes"
+	"github.com/pkg/errors"
+	"github.com/vmihailenco/msgpack"
+)
+
+// EncodeMessagePack encodes the client list as MessagePack.
+// Maps use the same keys as the JSON data file and times are MessagePack timestamps.
+func EncodeMessagePack(clientList ClientList) ([]byte, error) {
+	var buffer bytes.Buffer
+	err := msgpack.NewEncoder(&buffer).UseJSONTag(true).UseCompactEncoding(true).Encode(clientList)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return buffer.Bytes(), nil
+}
@@ -0,0 +1,72 @@
+package dataserver
+
+import (
+	"dataserver/internal/pkg/geo"
+)
+
+// NearbyClient is a client found by a spatial query with its distance in nautical miles.
+type NearbyClient struct {
+	Distance   float64 `json:"distance"`
+	Pilot      *Pilot  `json:"pilot,omitempty"`
+	Controller *ATC    `json:"controller,omitempty"`
+}
+
+// ClientsInBoundingBox finds every client positioned inside a bounding box.
+func (c *Context) ClientsInBoundingBox(b geo.BoundingBox) ClientList {
+	return c.ClientList.Snapshot().InBoundingBox(c.Index.InBoundingBox(b))
+}
+
+// ClientsWithinRadius finds every client within a radius in nautical miles of a point, nearest first.
+func (c *Context) ClientsWithinRadius(center geo.Point, radius float64) []NearbyClient {
+	return c.ClientList.Snapshot().Nearby(c.Index.WithinRadius(center, radius))
+}
+
+// NearestClients finds the k clients nearest to a point, nearest first.
+func (c *Context) NearestClients(center geo.Point, k int) []NearbyClient {
+	return c.ClientList.Snapshot().Nearby(c.Index.Nearest(center, k))
+}
+
+// InBoundingBox keeps the clients found by a bounding box query.
+func (c ClientList) InBoundingBox(results []geo.Result) ClientList {
+	found := map[string]bool{}
+	for _, v := range results {
+		found[v.Key] = true
+	}
+	clientList := ClientList{
+		PilotData: []Pilot{},
+		ATCData:   []ATC{},
+		Mutex:     c.Mutex,
+	}
+	for _, v := range c.PilotData {
+		if found[v.Callsign] {
+			clientList.PilotData = append(clientList.PilotData, v)
+		}
+	}
+	for _, v := range c.ATCData {
+		if found[v.Callsign] {
+			clientList.ATCData = append(clientList.ATCData, v)
+		}
+	}
+	return clientList
+}
+
+// Nearby matches the clients found by a radius or nearest query, keeping their order.
+func (c ClientList) Nearby(results []geo.Result) []NearbyClient {
+	pilots := map[string]int{}
+	for i, v := range c.PilotData {
+		pilots[v.Callsign] = i
+	}
+	controllers := map[string]int{}
+	for i, v := range c.ATCData {
+		controllers[v.Callsign] = i
+	}
+	nearby := []NearbyClient{}
+	for _, v := range results {
+		if i, ok := pilots[v.Key]; ok {
+			nearby = append(nearby, NearbyClient{Distance: v.Distance, Pilot: &c.PilotData[i]})
+		} else if i, ok := controllers[v.Key]; ok {
+			nearby = append(nearby, NearbyClient{Distance: v.Distance, Controller: &c.ATCData[i]})
+		}
+	}
+	return nearby
+}
@@ -2,6 +2,7 @@ package dataserver
 
 import (
 	"dataserver/internal/pkg/fsd"
+	"dataserver/internal/pkg/geo"
 	log "github.com/sirupsen/logrus"
 	"time"
 )
@@ -11,12 +12,15 @@ type Pilot struct {
 	Server      string     `json:"server"`
 	Callsign    string     `json:"callsign"`
 	Member      MemberData `json:"member"`
+	Rating      int        `json:"rating"`
 	Latitude    float64    `json:"latitude"`
 	Longitude   float64    `json:"longitude"`
 	Altitude    int        `json:"altitude"`
 	Speed       int        `json:"speed"`
 	Heading     int        `json:"heading"`
+	Transponder int        `json:"transponder"`
 	FlightPlan  FlightPlan `json:"plan"`
+	LogonTime   time.Time  `json:"logon_time"`
 	LastUpdated time.Time  `json:"last_updated"`
 }
 
@@ -30,14 +34,16 @@ func (c *Context) HandlePilotData(fields []string) error {
 	defer c.ClientList.Mutex.Unlock()
 	for i, v := range c.ClientList.PilotData {
 		if v.Callsign == pilotData.Callsign {
-			timeBetweenPilotUpdates.Observe(time.Since(*&c.ClientList.PilotData[i].LastUpdated).Seconds())
+			timeBetweenPilotUpdates.Observe(c.Clock.Now().Sub(*&c.ClientList.PilotData[i].LastUpdated).Seconds())
 			*&c.ClientList.PilotData[i].Latitude = pilotData.Latitude
 			*&c.ClientList.PilotData[i].Longitude = pilotData.Longitude
 			*&c.ClientList.PilotData[i].Altitude = pilotData.Altitude
 			*&c.ClientList.PilotData[i].Speed = pilotData.GroundSpeed
 			*&c.ClientList.PilotData[i].Heading = pilotData.Heading
-			*&c.ClientList.PilotData[i].LastUpdated = time.Now().UTC()
-			kafkaPush(c.Producer, c.ClientList.PilotData[i], "update_position")
+			*&c.ClientList.PilotData[i].Transponder = pilotData.Transponder
+			*&c.ClientList.PilotData[i].LastUpdated = c.Clock.Now().UTC()
+			c.Index.Update(v.Callsign, geo.Point{Latitude: pilotData.Latitude, Longitude: pilotData.Longitude})
+			c.publish(c.ClientList.PilotData[i], "update_position")
 			break
 		}
 	}
@@ -0,0 +1,232 @@
+package dataserver
+
+import (
+	"bufio"
+	"context"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OptOutList is the set of CIDs whose member data must never be published.
+type OptOutList struct {
+	Path     string
+	CIDs     map[int]bool
+	Modified time.Time
+	Mutex    *sync.RWMutex
+	// audited is the outputs each member has been redacted from since the list last changed
+	audited map[redaction]bool
+}
+
+// redaction is a member withheld from an output
+type redaction struct {
+	cid    int
+	output string
+}
+
+// auditLog marks entries which must be retained as a record of redactions.
+var auditLog = log.WithField("audit", true)
+
+// NewOptOutList creates the opt-out list from a file, if there is one.
+func NewOptOutList(path string) *OptOutList {
+	optOut := &OptOutList{
+		CIDs:  map[int]bool{},
+		Mutex: &sync.RWMutex{},
+	}
+	if path == "" {
+		log.Debug("Opt-out list not defined.")
+		return optOut
+	}
+	optOut.Path = path
+	err := optOut.Reload()
+	if err != nil {
+		log.WithField("error", err).Fatal("Failed to load opt-out list.")
+	}
+	return optOut
+}
+
+// SetPath switches to another opt-out file and reads it, clearing the list if the path is empty.
+func (o *OptOutList) SetPath(path string) error {
+	o.Mutex.Lock()
+	if o.Path == path {
+		o.Mutex.Unlock()
+		return nil
+	}
+	o.Path = path
+	if path == "" {
+		o.CIDs = map[int]bool{}
+		o.Modified = time.Time{}
+		o.audited = nil
+		auditLog.Info("Opt-out list cleared.")
+	}
+	o.Mutex.Unlock()
+	return o.Reload()
+}
+
+// path returns the opt-out file in use
+func (o *OptOutList) path() string {
+	o.Mutex.RLock()
+	defer o.Mutex.RUnlock()
+	return o.Path
+}
+
+// Reload reads the opt-out file again, one CID per line with # comments.
+func (o *OptOutList) Reload() error {
+	path := o.path()
+	if path == "" {
+		return nil
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	cids := map[int]bool{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.SplitN(scanner.Text(), "#", 2)[0])
+		if line == "" {
+			continue
+		}
+		cid, err := strconv.Atoi(line)
+		if err != nil {
+			return errors.Wrapf(err, "Failed to parse CID in opt-out list. %v", line)
+		}
+		cids[cid] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return errors.WithStack(err)
+	}
+	o.Mutex.Lock()
+	defer o.Mutex.Unlock()
+	o.CIDs = cids
+	o.Modified = info.ModTime()
+	o.audited = nil
+	auditLog.WithFields(log.Fields{
+		"path":  path,
+		"count": len(cids),
+	}).Info("Opt-out list loaded.")
+	return nil
+}
+
+// Contains checks whether a CID has opted out of publication.
+func (o *OptOutList) Contains(cid int) bool {
+	if o == nil {
+		return false
+	}
+	o.Mutex.RLock()
+	defer o.Mutex.RUnlock()
+	return o.CIDs[cid]
+}
+
+// RedactClientList returns a copy of the client list with opted out members removed.
+func (o *OptOutList) RedactClientList(clientList ClientList, output string) ClientList {
+	redacted := ClientList{
+		PilotData: make([]Pilot, len(clientList.PilotData)),
+		ATCData:   make([]ATC, len(clientList.ATCData)),
+		Mutex:     clientList.Mutex,
+	}
+	copy(redacted.PilotData, clientList.PilotData)
+	copy(redacted.ATCData, clientList.ATCData)
+	for i, v := range redacted.PilotData {
+		if o.Contains(v.Member.CID) {
+			o.auditRedaction(v.Member.CID, v.Callsign, output)
+			redacted.PilotData[i].Member = MemberData{}
+		}
+	}
+	for i, v := range redacted.ATCData {
+		if o.Contains(v.Member.CID) {
+			o.auditRedaction(v.Member.CID, v.Callsign, output)
+			redacted.ATCData[i].Member = MemberData{}
+		}
+	}
+	return redacted
+}
+
+// redact removes the member data from an event payload if the member has opted out.
+func (o *OptOutList) redact(data interface{}) interface{} {
+	switch v := data.(type) {
+	case Pilot:
+		if o.Contains(v.Member.CID) {
+			o.auditRedaction(v.Member.CID, v.Callsign, "events")
+			v.Member = MemberData{}
+		}
+		return v
+	case ATC:
+		if o.Contains(v.Member.CID) {
+			o.auditRedaction(v.Member.CID, v.Callsign, "events")
+			v.Member = MemberData{}
+		}
+		return v
+	case Session:
+		if o.Contains(v.Member.CID) {
+			o.auditRedaction(v.Member.CID, v.Callsign, "events")
+			v.Member = MemberData{}
+		}
+		return v
+	case Anomaly:
+		if o.Contains(v.CID) {
+			o.auditRedaction(v.CID, v.Callsign, "events")
+			v.CID = 0
+		}
+		return v
+	}
+	return data
+}
+
+// ReloadOptOutList checks the opt-out file every minute and reloads it when it changes.
+func (c *Context) ReloadOptOutList(ctx context.Context) {
+	c.every(ctx, func() time.Duration { return time.Minute }, func() {
+		path := c.OptOut.path()
+		if path == "" {
+			return
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			log.WithField("error", err).Error("Failed to check opt-out list.")
+			return
+		}
+		c.OptOut.Mutex.RLock()
+		modified := c.OptOut.Modified
+		c.OptOut.Mutex.RUnlock()
+		if !info.ModTime().After(modified) {
+			return
+		}
+		err = c.OptOut.Reload()
+		if err != nil {
+			log.WithField("error", err).Error("Failed to reload opt-out list.")
+		}
+	})
+}
+
+// auditRedaction records the first time a member's data is withheld from an output since the list last changed
+func (o *OptOutList) auditRedaction(cid int, callsign string, output string) {
+	key := redaction{cid: cid, output: output}
+	o.Mutex.Lock()
+	audited := o.audited[key]
+	if o.audited == nil {
+		o.audited = map[redaction]bool{}
+	}
+	o.audited[key] = true
+	o.Mutex.Unlock()
+	if !audited {
+		auditRedaction(cid, callsign, output)
+	}
+}
+
+// auditRedaction records that a member's data was withheld from an output
+func auditRedaction(cid int, callsign string, output string) {
+	auditLog.WithFields(log.Fields{
+		"cid":      cid,
+		"callsign": callsign,
+		"output":   output,
+	}).Info("Member data redacted.")
+}
@@ -0,0 +1,109 @@
+package dataserver
+
+import (
+	"github.com/sirupsen/logrus/hooks/test"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// writeOptOut writes an opt-out list to a temporary file
+func writeOptOut(t *testing.T, dir string, contents string) string {
+	path := filepath.Join(dir, "optout.txt")
+	if err := ioutil.WriteFile(path, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// redactions counts the audit entries written for redactions
+func redactions(hook *test.Hook) int {
+	count := 0
+	for _, entry := range hook.AllEntries() {
+		if entry.Message == "Member data redacted." {
+			count++
+		}
+	}
+	return count
+}
+
+func TestRedactClientList(t *testing.T) {
+	dir, err := ioutil.TempDir("", "privacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	optOut := NewOptOutList(writeOptOut(t, dir, "2 # pilot\n3\n"))
+	clientList := ClientList{
+		PilotData: []Pilot{
+			{Callsign: "AAL1", Member: MemberData{CID: 1, Name: "One"}},
+			{Callsign: "BAW2", Member: MemberData{CID: 2, Name: "Two"}},
+		},
+		ATCData: []ATC{
+			{Callsign: "EGLL_TWR", Member: MemberData{CID: 3, Name: "Three"}},
+		},
+		Mutex: &sync.RWMutex{},
+	}
+
+	hook := test.NewGlobal()
+	defer hook.Reset()
+	redacted := optOut.RedactClientList(clientList, "api")
+	want := []MemberData{{CID: 1, Name: "One"}, {}, {}}
+	got := []MemberData{redacted.PilotData[0].Member, redacted.PilotData[1].Member, redacted.ATCData[0].Member}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("RedactClientList() members = %+v, want %+v", got, want)
+	}
+	if clientList.PilotData[1].Member.CID != 2 {
+		t.Errorf("RedactClientList() changed the original client list")
+	}
+
+	tests := []struct {
+		name   string
+		action func()
+		want   int
+	}{
+		{"First redaction", func() {}, 2},
+		{"Same output", func() { optOut.RedactClientList(clientList, "api") }, 2},
+		{"Another output", func() { optOut.RedactClientList(clientList, "grpc") }, 4},
+		{"List reloaded", func() {
+			if err := optOut.Reload(); err != nil {
+				t.Fatal(err)
+			}
+			optOut.RedactClientList(clientList, "api")
+		}, 6},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.action()
+			if got := redactions(hook); got != tt.want {
+				t.Errorf("audit entries = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	optOut := &OptOutList{CIDs: map[int]bool{2: true}, Mutex: &sync.RWMutex{}}
+	tests := []struct {
+		name string
+		data interface{}
+		want interface{}
+	}{
+		{"Pilot", Pilot{Callsign: "BAW2", Member: MemberData{CID: 2, Name: "Two"}}, Pilot{Callsign: "BAW2"}},
+		{"Pilot not opted out", Pilot{Callsign: "AAL1", Member: MemberData{CID: 1, Name: "One"}}, Pilot{Callsign: "AAL1", Member: MemberData{CID: 1, Name: "One"}}},
+		{"ATC", ATC{Callsign: "EGLL_TWR", Member: MemberData{CID: 2, Name: "Two"}}, ATC{Callsign: "EGLL_TWR"}},
+		{"Session", Session{Callsign: "BAW2", Member: MemberData{CID: 2, Name: "Two"}}, Session{Callsign: "BAW2"}},
+		{"Anomaly", Anomaly{CID: 2, Callsign: "BAW2"}, Anomaly{Callsign: "BAW2"}},
+		{"Other", "BAW2", "BAW2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := optOut.redact(tt.data); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("redact() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
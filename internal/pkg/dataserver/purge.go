@@ -0,0 +1,276 @@
+package dataserver
+
+import (
+	"encoding/json"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+// PurgeStore keeps outputs a member's data can be purged from, such as the data directory or a bucket.
+// Names are slash separated, and precompressed variants are named after their original.
+type PurgeStore interface {
+	// List names every stored file, precompressed variants included.
+	List() ([]string, error)
+	// Read returns a stored file as it is stored.
+	Read(name string) ([]byte, error)
+	// Write replaces an output, or its variant with the encoding unless the encoding is empty.
+	Write(name string, data []byte, encoding Encoding) error
+	// Remove deletes a stored file.
+	Remove(name string) error
+}
+
+// storedOutput is an output along with which of its original and precompressed variants are stored
+type storedOutput struct {
+	name      string
+	original  bool
+	encodings []Encoding
+}
+
+// PurgeMember erases a CID's member data from every file stored below the data directory.
+func PurgeMember(sink FileSink, cid int) error {
+	return PurgeStored(directoryStore(sink.Directory), cid)
+}
+
+// PurgeStored erases a CID's member data from every output in a store and its precompressed variants,
+// including variants whose original is gone. JSON is rewritten without the member, while the other
+// formats are encoded again from the purged data file, or removed if there is no data file to encode.
+func PurgeStored(store PurgeStore, cid int) error {
+	names, err := store.List()
+	if err != nil {
+		return err
+	}
+	p := purger{cid: cid}
+	for _, output := range groupOutputs(names) {
+		err = p.purge(store, output)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupOutputs gathers stored files by the output they hold, the data file first so the formats can be encoded from it
+func groupOutputs(names []string) []storedOutput {
+	outputs := map[string]*storedOutput{}
+	for _, v := range names {
+		name, encoding := variantOf(v)
+		output, ok := outputs[name]
+		if !ok {
+			output = &storedOutput{name: name}
+			outputs[name] = output
+		}
+		if encoding == "" {
+			output.original = true
+		} else {
+			output.encodings = append(output.encodings, encoding)
+		}
+	}
+	sorted := make([]storedOutput, 0, len(outputs))
+	for _, v := range outputs {
+		sorted = append(sorted, *v)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		if (sorted[i].name == dataFileName) != (sorted[j].name == dataFileName) {
+			return sorted[i].name == dataFileName
+		}
+		return sorted[i].name < sorted[j].name
+	})
+	return sorted
+}
+
+// purger erases a member from outputs, keeping the purged data file the other formats are encoded from
+type purger struct {
+	cid        int
+	clientList *ClientList
+	purged     bool
+}
+
+// purge rewrites an output and every stored variant of it without the member's data
+func (p *purger) purge(store PurgeStore, output storedOutput) error {
+	if !p.handles(output.name) {
+		return nil
+	}
+	data, err := readOutput(store, output)
+	if err != nil {
+		return err
+	}
+	purged, changed, err := p.purgeData(output.name, data)
+	if err != nil || !changed {
+		return err
+	}
+	var stored []string
+	if output.original {
+		stored = append(stored, output.name)
+	}
+	for _, v := range output.encodings {
+		stored = append(stored, v.FileName(output.name))
+	}
+	if purged == nil {
+		for _, v := range stored {
+			err = store.Remove(v)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if output.original {
+		err = store.Write(output.name, purged, "")
+		if err != nil {
+			return err
+		}
+	}
+	for _, v := range output.encodings {
+		err = store.Write(output.name, purged, v)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// handles checks if an output can hold member data
+func (p *purger) handles(name string) bool {
+	_, isFormat := FormatOf(name)
+	return isFormat || path.Ext(name) == ".json"
+}
+
+// purgeData erases the member from an output's contents, returning nil contents if it must be removed instead
+func (p *purger) purgeData(name string, data []byte) ([]byte, bool, error) {
+	if name == dataFileName {
+		purged, changed, err := purgeJSON(data, p.cid, name)
+		if err != nil {
+			return nil, false, err
+		}
+		current := data
+		if changed {
+			current = purged
+		}
+		var clientList ClientList
+		if json.Unmarshal(current, &clientList) == nil {
+			p.clientList = &clientList
+		}
+		p.purged = changed
+		return purged, changed, nil
+	}
+	if format, ok := FormatOf(name); ok {
+		if p.clientList == nil {
+			return nil, true, nil
+		}
+		if !p.purged {
+			return nil, false, nil
+		}
+		encoded, err := formatEncoders[format].encode(*p.clientList, nil)
+		return encoded, true, err
+	}
+	return purgeJSON(data, p.cid, name)
+}
+
+// readOutput reads an output, decompressing a variant if the original is not stored
+func readOutput(store PurgeStore, output storedOutput) ([]byte, error) {
+	if output.original {
+		return store.Read(output.name)
+	}
+	encoding := output.encodings[0]
+	data, err := store.Read(encoding.FileName(output.name))
+	if err != nil {
+		return nil, err
+	}
+	return encoding.Decompress(data)
+}
+
+// purgeJSON erases a CID's member data from a JSON document, reporting if anything changed.
+// Documents which are not JSON are left unchanged.
+func purgeJSON(file []byte, cid int, name string) ([]byte, bool, error) {
+	var data interface{}
+	err := json.Unmarshal(file, &data)
+	if err != nil {
+		log.WithField("path", name).Debug("Skipping file which is not JSON.")
+		return nil, false, nil
+	}
+	if !purgeValue(data, float64(cid), name) {
+		return nil, false, nil
+	}
+	purged, err := json.Marshal(data)
+	if err != nil {
+		return nil, false, errors.WithStack(err)
+	}
+	return purged, true, nil
+}
+
+// purgeValue walks decoded JSON clearing every object carrying the CID, reporting if anything changed
+func purgeValue(data interface{}, cid float64, path string) bool {
+	purged := false
+	switch v := data.(type) {
+	case map[string]interface{}:
+		if v["cid"] == cid {
+			callsign, _ := v["callsign"].(string)
+			auditRedaction(int(cid), callsign, path)
+			v["cid"] = 0
+			if _, ok := v["name"]; ok {
+				v["name"] = ""
+			}
+			purged = true
+		}
+		for _, child := range v {
+			purged = purgeValue(child, cid, path) || purged
+		}
+	case []interface{}:
+		for _, child := range v {
+			purged = purgeValue(child, cid, path) || purged
+		}
+	}
+	return purged
+}
+
+// directoryStore keeps outputs in the data directory
+type directoryStore string
+
+// List names every file below the data directory.
+func (d directoryStore) List() ([]string, error) {
+	var names []string
+	err := filepath.Walk(string(d), func(file string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if info.IsDir() {
+			return nil
+		}
+		name, err := filepath.Rel(string(d), file)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		names = append(names, filepath.ToSlash(name))
+		return nil
+	})
+	return names, err
+}
+
+// Read returns a file from the data directory.
+func (d directoryStore) Read(name string) ([]byte, error) {
+	data, err := ioutil.ReadFile(filepath.Join(string(d), filepath.FromSlash(name)))
+	return data, errors.WithStack(err)
+}
+
+// Write replaces a file in the data directory, or its variant with the encoding.
+func (d directoryStore) Write(name string, data []byte, encoding Encoding) error {
+	if encoding != "" {
+		compressed, err := encoding.Compress(data)
+		if err != nil {
+			return err
+		}
+		data = compressed
+		name = encoding.FileName(name)
+	}
+	return writeFile(filepath.Join(string(d), filepath.FromSlash(name)), data)
+}
+
+// Remove deletes a file from the data directory.
+func (d directoryStore) Remove(name string) error {
+	return errors.WithStack(os.Remove(filepath.Join(string(d), filepath.FromSlash(name))))
+}
@@ -0,0 +1,165 @@
+package dataserver
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPurgeValue(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		want       string
+		wantPurged bool
+	}{
+		{"Pilot", `{"callsign":"BAW2","cid":2,"name":"Two"}`, `{"callsign":"BAW2","cid":0,"name":""}`, true},
+		{"Nested", `{"pilots":[{"member":{"cid":2,"name":"Two"}},{"member":{"cid":1,"name":"One"}}]}`, `{"pilots":[{"member":{"cid":0,"name":""}},{"member":{"cid":1,"name":"One"}}]}`, true},
+		{"Anomaly", `[{"cid":2,"callsigns":["BAW2"]}]`, `[{"callsigns":["BAW2"],"cid":0}]`, true},
+		{"Other member", `{"cid":1,"name":"One"}`, `{"cid":1,"name":"One"}`, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data interface{}
+			if err := json.Unmarshal([]byte(tt.data), &data); err != nil {
+				t.Fatal(err)
+			}
+			purged := purgeValue(data, 2, "test.json")
+			got, _ := json.Marshal(data)
+			if purged != tt.wantPurged || string(got) != tt.want {
+				t.Errorf("purgeValue() = %v, %s, want %v, %s", purged, got, tt.wantPurged, tt.want)
+			}
+		})
+	}
+}
+
+// writeOutputs writes every output of a client list to a sink, along with extra files
+func writeOutputs(t *testing.T, sink FileSink, clientList ClientList, files map[string]string) {
+	data, err := json.Marshal(clientList)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sink.Write(dataFileName, data); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []Format{FormatGeoJSON, FormatKMZ, FormatMessagePack} {
+		data, err := formatEncoders[v].encode(clientList, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := sink.Write(formatEncoders[v].fileName, data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, data := range files {
+		if err := sink.Write(name, []byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// readStored reads a file from the data directory, decompressing precompressed variants
+func readStored(t *testing.T, dir string, name string) string {
+	data, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, encoding := variantOf(name); encoding != "" {
+		data, err = encoding.Decompress(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return string(data)
+}
+
+func TestPurgeMember(t *testing.T) {
+	dir, err := ioutil.TempDir("", "purge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sink := FileSink{Directory: dir, Encodings: []Encoding{EncodingGzip}}
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	clientList := ClientList{
+		PilotData: []Pilot{
+			{Callsign: "AAL1", Member: MemberData{CID: 1, Name: "Member One"}, Latitude: 40.64, Longitude: -73.78, LastUpdated: now},
+			{Callsign: "BAW2", Member: MemberData{CID: 2, Name: "Member Two"}, Latitude: 51.47, Longitude: -0.46, LastUpdated: now},
+		},
+	}
+	files := map[string]string{
+		"deltas/1-2.json": `{"added":[{"member":{"cid":2,"name":"Two"}}]}`,
+		"deltas/2-3.json": `{"added":[{"member":{"cid":1,"name":"One"}}]}`,
+		"notes.txt":       `cid 2`,
+	}
+	writeOutputs(t, sink, clientList, files)
+	// Leave only the precompressed copy of a delta, as the bucket or an older sink may have
+	if err := os.Remove(filepath.Join(dir, "deltas", "1-2.json")); err != nil {
+		t.Fatal(err)
+	}
+	if err := PurgeMember(sink, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		contains string
+	}{
+		{"vatsim-data.json", "BAW2"},
+		{"vatsim-data.json.gz", "BAW2"},
+		{"vatsim-data.geojson", "BAW2"},
+		{"vatsim-data.geojson.gz", "BAW2"},
+		{"vatsim-data.msgpack", "BAW2"},
+		{"vatsim-data.msgpack.gz", "BAW2"},
+		{"deltas/1-2.json.gz", `"cid":0`},
+		{"deltas/2-3.json", "One"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := readStored(t, dir, tt.name)
+			if strings.Contains(data, "Two") {
+				t.Errorf("PurgeMember() left the member's name in %s", tt.name)
+			}
+			if !strings.Contains(data, tt.contains) {
+				t.Errorf("PurgeMember() left %s without %s", tt.name, tt.contains)
+			}
+		})
+	}
+	if _, err := os.Stat(filepath.Join(dir, "deltas", "1-2.json")); !os.IsNotExist(err) {
+		t.Errorf("PurgeMember() wrote a delta which was only stored precompressed")
+	}
+	if data := readStored(t, dir, "notes.txt"); data != files["notes.txt"] {
+		t.Errorf("PurgeMember() changed notes.txt to %s", data)
+	}
+}
+
+func TestPurgeMemberWithoutDataFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "purge")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sink := FileSink{Directory: dir, Encodings: []Encoding{EncodingGzip}}
+	clientList := ClientList{
+		PilotData: []Pilot{{Callsign: "BAW2", Member: MemberData{CID: 2, Name: "Member Two"}}},
+	}
+	writeOutputs(t, sink, clientList, nil)
+	if err := os.Remove(filepath.Join(dir, dataFileName)); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(filepath.Join(dir, EncodingGzip.FileName(dataFileName))); err != nil {
+		t.Fatal(err)
+	}
+	if err := PurgeMember(sink, 2); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"vatsim-data.geojson", "vatsim-data.geojson.gz", "vatsim-data.kmz", "vatsim-data.msgpack"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); !os.IsNotExist(err) {
+			t.Errorf("PurgeMember() kept %s with nothing to encode it from", name)
+		}
+	}
+}
@@ -12,41 +12,19 @@ func (c *Context) RemoveClient(fields []string) error {
 	if err != nil {
 		return err
 	}
+	var ended []Session
+	// Deferred before the unlock, so sessions are recorded once the lock is released
+	defer func() { c.recordSessions(ended) }()
 	c.ClientList.Mutex.Lock()
 	defer c.ClientList.Mutex.Unlock()
-	for i, v := range c.ClientList.PilotData {
-		if v.Callsign == removeClient.Callsign {
-			*&c.ClientList.PilotData = append(c.ClientList.PilotData[:i], c.ClientList.PilotData[i+1:]...)
-			data := Pilot{
-				Server:   "",
-				Callsign: removeClient.Callsign,
-				Member: MemberData{
-					CID:  0,
-					Name: "",
-				},
-			}
-
-			kafkaPush(c.Producer, data, "remove_client")
-			break
-		}
+	if pilot, found := c.ClientList.removePilot(removeClient.Callsign); found {
+		totalConnections.With(prometheus.Labels{"server": pilot.Server}).Dec()
+		ended = append(ended, c.endSession(pilot.session(SessionEndRemoved, c.Clock.Now())))
 	}
-	for i, v := range c.ClientList.ATCData {
-		if v.Callsign == removeClient.Callsign {
-			*&c.ClientList.ATCData = append(c.ClientList.ATCData[:i], c.ClientList.ATCData[i+1:]...)
-			data := ATC{
-				Server:   "",
-				Callsign: removeClient.Callsign,
-				Rating:   0,
-				Member: MemberData{
-					CID:  0,
-					Name: "",
-				},
-			}
-			kafkaPush(c.Producer, data, "remove_client")
-			break
-		}
+	if atc, found := c.ClientList.removeATC(removeClient.Callsign); found {
+		totalConnections.With(prometheus.Labels{"server": atc.Server}).Dec()
+		ended = append(ended, c.endSession(atc.session(SessionEndRemoved, c.Clock.Now())))
 	}
-	totalConnections.With(prometheus.Labels{"server": removeClient.Source}).Dec()
 	log.WithFields(log.Fields{
 		"callsign": removeClient.Callsign,
 		"server":   removeClient.Source,
@@ -54,3 +32,25 @@ func (c *Context) RemoveClient(fields []string) error {
 	Channel <- *c.ClientList
 	return nil
 }
+
+// SendRemoveClient removes our fake clients from the network
+func (c *Context) SendRemoveClient() {
+	name := c.Configuration().Data.Server.Name
+	for _, v := range c.Presences {
+		removeClient := fsd.RemoveClient{
+			Base: fsd.Base{
+				Destination: fsd.Everyone,
+				Source:      name,
+				HopCount:    1,
+			},
+			Callsign: v.Callsign,
+		}
+		err := c.Writer.Send(&removeClient)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"packet": removeClient.Serialize(),
+				"error":  err,
+			}).Error("Failed to send RMCLIENT packet to FSD server.")
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package dataserver
+
+import (
+	"dataserver/internal/pkg/logbook"
+	log "github.com/sirupsen/logrus"
+	"time"
+)
+
+// Session summarises a client's time on the network once they disconnect.
+type Session struct {
+	Server     string      `json:"server"`
+	Callsign   string      `json:"callsign"`
+	ClientType ClientType  `json:"type"`
+	Member     MemberData  `json:"member"`
+	Rating     int         `json:"rating"`
+	Latitude   float64     `json:"latitude"`
+	Longitude  float64     `json:"longitude"`
+	Altitude   int         `json:"altitude"`
+	FlightPlan *FlightPlan `json:"plan,omitempty"`
+	LogonTime  time.Time   `json:"logon_time"`
+	LogoffTime time.Time   `json:"logoff_time"`
+	Duration   int         `json:"duration"`
+	Reason     string      `json:"reason"`
+}
+
+// Reasons a session has ended.
+const (
+	SessionEndRemoved     = "removed"
+	SessionEndTimedOut    = "timed_out"
+	SessionEndChangedType = "changed_type"
+)
+
+// session summarises the pilot's connection ending at the time given
+func (p Pilot) session(reason string, now time.Time) Session {
+	var flightPlan *FlightPlan
+	if p.FlightPlan != (FlightPlan{}) {
+		flightPlan = &p.FlightPlan
+	}
+	return Session{
+		Server:     p.Server,
+		Callsign:   p.Callsign,
+		ClientType: ClientTypePilot,
+		Member:     p.Member,
+		Rating:     p.Rating,
+		Latitude:   p.Latitude,
+		Longitude:  p.Longitude,
+		Altitude:   p.Altitude,
+		FlightPlan: flightPlan,
+		LogonTime:  p.LogonTime,
+		LogoffTime: now.UTC(),
+		Duration:   int(now.Sub(p.LogonTime).Seconds()),
+		Reason:     reason,
+	}
+}
+
+// session summarises the controller's connection ending at the time given
+func (a ATC) session(reason string, now time.Time) Session {
+	return Session{
+		Server:     a.Server,
+		Callsign:   a.Callsign,
+		ClientType: a.ClientType,
+		Member:     a.Member,
+		Rating:     a.Rating,
+		Latitude:   a.Latitude,
+		Longitude:  a.Longitude,
+		LogonTime:  a.LogonTime,
+		LogoffTime: now.UTC(),
+		Duration:   int(now.Sub(a.LogonTime).Seconds()),
+		Reason:     reason,
+	}
+}
+
+// entry converts the session into a logbook entry
+func (s Session) entry() logbook.Entry {
+	entry := logbook.Entry{
+		CID:        s.Member.CID,
+		Callsign:   s.Callsign,
+		Type:       string(s.ClientType),
+		Server:     s.Server,
+		Rating:     s.Rating,
+		LogonTime:  s.LogonTime,
+		LogoffTime: s.LogoffTime,
+	}
+	if s.FlightPlan != nil {
+		entry.Departure = s.FlightPlan.Departure
+		entry.Arrival = s.FlightPlan.Arrival
+	}
+	return entry
+}
+
+// endSession publishes a client's completed session while the client list is locked,
+// returning it to be recorded once the lock is released
+func (c *Context) endSession(session Session) Session {
+	c.Index.Remove(session.Callsign)
+	c.forgetDuplicateCID(session.Member.CID, session.Callsign)
+	c.publish(session, "remove_client")
+	return session
+}
+
+// recordSessions writes completed sessions to the logbook, which must not be done
+// while holding the client list lock as it waits for the disk
+func (c *Context) recordSessions(sessions []Session) {
+	for _, v := range sessions {
+		err := c.Logbook.Record(v.entry())
+		if err != nil {
+			log.WithField("error", err).Error("Failed to record session in logbook.")
+		}
+	}
+}
@@ -1,22 +1,24 @@
 package dataserver
 
 import (
-	"dataserver/internal/pkg/config"
 	"dataserver/internal/pkg/fsd"
 	log "github.com/sirupsen/logrus"
 )
 
 // SendSync sends a sync packet to the FSD server.
 func (c *Context) SendSync() {
-	name, err := config.Cfg.String("data.server.name")
-	if err != nil {
-		log.Fatal("Data server name not defined.")
+	sync := fsd.Sync{
+		Base: fsd.Base{
+			Destination: fsd.Everyone,
+			Source:      c.Configuration().Data.Server.Name,
+			HopCount:    1,
+		},
 	}
-	err = fsd.Send(c.Consumer, "SYNC:*:"+name+":B1:1:")
+	err := c.Writer.Send(&sync)
 	if err != nil {
 		log.WithFields(log.Fields{
-			"connection": c.Consumer,
-			"error":      err,
-		}).Fatal("Failed to send SYNC packet to FSD server.")
+			"packet": sync.Serialize(),
+			"error":  err,
+		}).Error("Failed to send SYNC packet to FSD server.")
 	}
 }
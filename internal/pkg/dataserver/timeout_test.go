@@ -0,0 +1,158 @@
+package dataserver
+
+import (
+	"context"
+	"dataserver/internal/pkg/clock"
+	"dataserver/internal/pkg/config"
+	"dataserver/internal/pkg/geo"
+	"dataserver/internal/pkg/logbook"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestContext creates a context with no outputs running on a fake clock
+func newTestContext(cfg *config.Config, fake *clock.Fake) *Context {
+	return &Context{
+		Config:     cfg,
+		ClientList: &ClientList{Mutex: &sync.RWMutex{}},
+		OptOut:     NewOptOutList(""),
+		Events:     NewEventBus(10),
+		Index:      geo.NewIndex(1),
+		Mutex:      &sync.RWMutex{},
+		Clock:      fake,
+	}
+}
+
+func TestCheckForTimeouts(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	c := newTestContext(&config.Config{
+		Timeout: config.Timeout{Pilot: 2 * time.Minute, Controller: 10 * time.Minute},
+	}, fake)
+	c.ClientList.PilotData = []Pilot{{Callsign: "AAL1", LastUpdated: start}}
+	c.ClientList.ATCData = []ATC{{Callsign: "EGLL_TWR", LastUpdated: start}}
+
+	tests := []struct {
+		name        string
+		advance     time.Duration
+		pilots      int
+		controllers int
+	}{
+		{"Before either timeout", time.Minute, 1, 1},
+		{"After the pilot timeout", 2 * time.Minute, 0, 1},
+		{"After the controller timeout", 10 * time.Minute, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake.Advance(tt.advance)
+			c.checkForTimeouts()
+			if len(c.ClientList.PilotData) != tt.pilots || len(c.ClientList.ATCData) != tt.controllers {
+				t.Errorf("checkForTimeouts() kept %d pilots and %d controllers, want %d and %d",
+					len(c.ClientList.PilotData), len(c.ClientList.ATCData), tt.pilots, tt.controllers)
+			}
+		})
+	}
+}
+
+func TestCheckForTimeoutsRecordsUnlocked(t *testing.T) {
+	directory, err := ioutil.TempDir("", "logbook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	sessions, err := logbook.Open(filepath.Join(directory, "logbook.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sessions.Close()
+
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	fake := clock.NewFake(start)
+	c := newTestContext(&config.Config{
+		Timeout: config.Timeout{Pilot: time.Minute, Controller: time.Minute},
+	}, fake)
+	c.Logbook = sessions
+	c.ClientList.PilotData = []Pilot{{Callsign: "AAL1", Member: MemberData{CID: 1}, LastUpdated: start}}
+	fake.Advance(time.Minute)
+
+	// A write in progress makes recording the session wait, as a slow disk would
+	tx, err := sessions.DB.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = tx.Exec("INSERT INTO sessions (cid, callsign, type, server, rating, logon_time, logoff_time) VALUES (2, 'BAW2', 'pilot', '', 1, 0, 0)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		c.checkForTimeouts()
+		close(done)
+	}()
+	locked := make(chan struct{})
+	go func() {
+		for {
+			c.ClientList.Mutex.RLock()
+			removed := len(c.ClientList.PilotData) == 0
+			c.ClientList.Mutex.RUnlock()
+			if removed {
+				close(locked)
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}()
+	select {
+	case <-locked:
+	case <-done:
+		t.Fatal("checkForTimeouts() returned while the logbook was busy")
+	case <-time.After(time.Second):
+		t.Fatal("client list stayed locked while recording the session")
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	<-done
+	hours, err := sessions.HoursByCID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hours) != 1 || hours[0].Sessions != 1 {
+		t.Errorf("HoursByCID() = %+v, want one recorded session", hours)
+	}
+}
+
+func TestEvery(t *testing.T) {
+	fake := clock.NewFake(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	c := newTestContext(&config.Config{
+		Timers: config.Timers{TimeoutCheck: time.Minute},
+	}, fake)
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := make(chan struct{}, 10)
+	stopped := make(chan struct{})
+	go func() {
+		c.every(ctx, c.interval(func(t config.Timers) time.Duration { return t.TimeoutCheck }), func() {
+			calls <- struct{}{}
+		})
+		close(stopped)
+	}()
+
+	for i := 0; i < 3; i++ {
+		fake.BlockUntil(1)
+		fake.Advance(time.Minute)
+		<-calls
+	}
+	fake.BlockUntil(1)
+	fake.Advance(30 * time.Second)
+	select {
+	case <-calls:
+		t.Error("every() called before the interval passed")
+	case <-time.After(10 * time.Millisecond):
+	}
+	cancel()
+	<-stopped
+}
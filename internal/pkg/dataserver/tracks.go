@@ -0,0 +1,67 @@
+package dataserver
+
+import (
+	"time"
+)
+
+// TrackPoint is a recorded pilot position.
+type TrackPoint struct {
+	Latitude  float64
+	Longitude float64
+	Altitude  int
+	Time      time.Time
+}
+
+// Tracks keeps the most recent positions of each pilot between data file updates.
+type Tracks struct {
+	length int
+	points map[string][]TrackPoint
+}
+
+// NewTracks creates a track recorder keeping the given number of positions per pilot.
+// No tracks are kept if the length is zero.
+func NewTracks(length int) *Tracks {
+	if length <= 0 {
+		return nil
+	}
+	return &Tracks{
+		length: length,
+		points: map[string][]TrackPoint{},
+	}
+}
+
+// Record adds the position of every pilot who has moved since the last update
+// and forgets pilots who have disconnected.
+func (t *Tracks) Record(clientList ClientList) {
+	if t == nil {
+		return
+	}
+	points := make(map[string][]TrackPoint, len(clientList.PilotData))
+	for _, v := range clientList.PilotData {
+		track := t.points[v.Callsign]
+		if v.LastUpdated.IsZero() {
+			continue
+		}
+		if n := len(track); n == 0 || track[n-1].Latitude != v.Latitude || track[n-1].Longitude != v.Longitude || track[n-1].Altitude != v.Altitude {
+			track = append(track, TrackPoint{
+				Latitude:  v.Latitude,
+				Longitude: v.Longitude,
+				Altitude:  v.Altitude,
+				Time:      v.LastUpdated,
+			})
+		}
+		if len(track) > t.length {
+			track = track[len(track)-t.length:]
+		}
+		points[v.Callsign] = track
+	}
+	t.points = points
+}
+
+// Track returns the recorded positions of a pilot, oldest first.
+func (t *Tracks) Track(callsign string) []TrackPoint {
+	if t == nil {
+		return nil
+	}
+	return t.points[callsign]
+}
@@ -0,0 +1,33 @@
+package dataserver
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracksRecord(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tracks := NewTracks(2)
+	for _, altitude := range []int{1000, 2000, 3000, 3000} {
+		tracks.Record(ClientList{
+			PilotData: []Pilot{
+				{Callsign: "AAL1", Altitude: altitude, LastUpdated: now},
+				{Callsign: "BAW2"},
+			},
+		})
+	}
+	track := tracks.Track("AAL1")
+	if len(track) != 2 || track[0].Altitude != 2000 || track[1].Altitude != 3000 {
+		t.Errorf("Track() = %v, want altitudes 2000 and 3000", track)
+	}
+	if track := tracks.Track("BAW2"); track != nil {
+		t.Errorf("Track() = %v before a position was sent, want nil", track)
+	}
+	tracks.Record(ClientList{})
+	if track := tracks.Track("AAL1"); track != nil {
+		t.Errorf("Track() = %v after disconnecting, want nil", track)
+	}
+	if NewTracks(0) != nil {
+		t.Errorf("NewTracks(0) should not keep tracks")
+	}
+}
@@ -0,0 +1,21 @@
+package dataserver
+
+import (
+	"dataserver/internal/pkg/config"
+)
+
+// ValidateConfig checks the client types, encodings and formats every output is configured with.
+func ValidateConfig(cfg *config.Config) config.Errors {
+	var errs config.Errors
+	errs.Check("data.file.types", validateClientTypes(cfg.Data.File.Types))
+	errs.Check("data.file.compression", validateEncodings(cfg.Data.File.Compression))
+	errs.Check("data.file.formats", validateFormats(cfg.Data.File.Formats))
+	errs.Check("api.types", validateClientTypes(cfg.API.Types))
+	errs.Check("rpc.types", validateClientTypes(cfg.RPC.Types))
+	errs.Check("kafka.types", validateClientTypes(cfg.Kafka.Types))
+	for _, v := range cfg.S3 {
+		errs.Check("s3."+v.Name+".compression", validateEncodings(v.Compression))
+		errs.Check("s3."+v.Name+".formats", validateFormats(v.Formats))
+	}
+	return errs
+}
@@ -1,16 +1,185 @@
 package fsd
 
-import "strings"
+import (
+	"github.com/pkg/errors"
+	"strconv"
+	"strings"
+)
+
+// Routing is how a packet travels between servers
+type Routing int
+
+const (
+	// Broadcast packets are passed on to every server.
+	Broadcast Routing = iota
+	// Unicast packets are passed towards the server their destination is on.
+	Unicast
+)
+
+// String is the prefix of the packet number marking the routing
+func (r Routing) String() string {
+	if r == Unicast {
+		return "U"
+	}
+	return "B"
+}
+
+// parseRouting reads the prefix of a packet number
+func parseRouting(prefix byte) (Routing, error) {
+	switch prefix {
+	case 'B':
+		return Broadcast, nil
+	case 'U':
+		return Unicast, nil
+	}
+	return Broadcast, errors.Errorf("Unknown routing %q.", prefix)
+}
+
+// Everyone is the destination of packets for every server and client.
+const Everyone = "*"
+
+// Multicast destinations of packets for every controller or every pilot.
+const (
+	EveryController = "*A"
+	EveryPilot      = "*P"
+)
+
+// clientPrefix marks a destination which is a client's callsign
+const clientPrefix = "%%"
+
+// frequencyPrefix marks a multicast destination which is every client on a frequency
+const frequencyPrefix = "@"
+
+// ClientDestination is the destination of packets for the client with callsign.
+func ClientDestination(callsign string) string {
+	return clientPrefix + callsign
+}
 
 // Base contains the common fields of all inter-server packets
 type Base struct {
 	Destination  string
 	Source       string
+	Routing      Routing
 	PacketNumber int
 	HopCount     int
 }
 
+// Validate checks the destination is everyone, a multicast group, a client or a server ident
+// and can be reached with the routing.
+func (b Base) Validate() error {
+	if !validIdent(b.Source) {
+		return errors.Errorf("Invalid source %q.", b.Source)
+	}
+	switch {
+	case b.Destination == Everyone:
+		if b.Routing == Unicast {
+			return errors.Errorf("Unicast packets can't be sent to everyone.")
+		}
+	case b.Destination == EveryController || b.Destination == EveryPilot:
+		if b.Routing == Unicast {
+			return errors.Errorf("Unicast packets can't be sent to %q.", b.Destination)
+		}
+	case strings.HasPrefix(b.Destination, frequencyPrefix):
+		if b.Routing == Unicast {
+			return errors.Errorf("Unicast packets can't be sent to %q.", b.Destination)
+		}
+		if !validFrequency(strings.TrimPrefix(b.Destination, frequencyPrefix)) {
+			return errors.Errorf("Invalid frequency destination %q.", b.Destination)
+		}
+	case strings.HasPrefix(b.Destination, clientPrefix):
+		if !validIdent(strings.TrimPrefix(b.Destination, clientPrefix)) {
+			return errors.Errorf("Invalid client destination %q.", b.Destination)
+		}
+	case !validIdent(b.Destination):
+		return errors.Errorf("Invalid destination %q.", b.Destination)
+	}
+	return nil
+}
+
+// validIdent checks a callsign or server ident can be written in a packet
+func validIdent(ident string) bool {
+	return ident != "" && !strings.ContainsAny(ident, ":*%@ \t\r\n")
+}
+
+// validFrequency checks a frequency is written as digits, such as 18300 for 118.300 MHz
+func validFrequency(frequency string) bool {
+	if frequency == "" {
+		return false
+	}
+	for _, v := range frequency {
+		if v < '0' || v > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// header starts serializing a packet with command and the common fields
+func (b Base) header(command string) *strings.Builder {
+	msg := &strings.Builder{}
+	msg.WriteString(command)
+	msg.WriteString(":")
+	msg.WriteString(b.Destination)
+	msg.WriteString(":")
+	msg.WriteString(b.Source)
+	msg.WriteString(":")
+	msg.WriteString(b.Routing.String())
+	msg.WriteString(strconv.Itoa(b.PacketNumber))
+	msg.WriteString(":")
+	msg.WriteString(strconv.Itoa(b.HopCount))
+	return msg
+}
+
+// DeserializeBase reads the fields common to every packet.
+func DeserializeBase(fields []string) (Base, error) {
+	return parseBase(fields, 5)
+}
+
+// parseBase reads the common fields of a packet with at least length fields
+func parseBase(fields []string, length int) (Base, error) {
+	if len(fields) < length || len(fields) < 5 {
+		return Base{}, errors.Errorf("Invalid %v packet. %v", fields[0], reassemble(fields))
+	}
+	if len(fields[3]) < 2 {
+		return Base{}, errors.Errorf("Missing packet number. %v", reassemble(fields))
+	}
+	routing, err := parseRouting(fields[3][0])
+	if err != nil {
+		return Base{}, errors.Wrapf(err, "Failed to parse routing. %v", reassemble(fields))
+	}
+	packetNumber, err := strconv.Atoi(fields[3][1:])
+	if err != nil {
+		return Base{}, errors.Wrapf(err, "Failed to parse packet number. %v", reassemble(fields))
+	}
+	hopCount, err := strconv.Atoi(fields[4])
+	if err != nil {
+		return Base{}, errors.Wrapf(err, "Failed to parse hop count. %v", reassemble(fields))
+	}
+	base := Base{
+		Destination:  fields[1],
+		Source:       fields[2],
+		Routing:      routing,
+		PacketNumber: packetNumber,
+		HopCount:     hopCount,
+	}
+	if err := base.Validate(); err != nil {
+		return Base{}, errors.Wrapf(err, "Invalid routing. %v", reassemble(fields))
+	}
+	return base, nil
+}
+
 // reassemble puts the FSD packet back together for debugging
 func reassemble(fields []string) string {
 	return strings.Join(fields, ":")
 }
+
+// Packet is an FSD packet the Writer numbers and sends
+type Packet interface {
+	Serialize() string
+	base() *Base
+}
+
+// base lets the Writer number every packet embedding Base
+func (b *Base) base() *Base {
+	return b
+}
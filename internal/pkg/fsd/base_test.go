@@ -0,0 +1,78 @@
+package fsd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseBase(t *testing.T) {
+	tests := []struct {
+		name    string
+		packet  string
+		want    Base
+		wantErr bool
+	}{
+		{"Broadcast", "PING:*:SERVER1:B12:3:data", Base{Destination: "*", Source: "SERVER1", Routing: Broadcast, PacketNumber: 12, HopCount: 3}, false},
+		{"Unicast to client", "MC:%%EGLL_ATIS:SERVER1:U7:1:25:EGLL_ATIS:DATA:T:Information A", Base{Destination: "%%EGLL_ATIS", Source: "SERVER1", Routing: Unicast, PacketNumber: 7, HopCount: 1}, false},
+		{"Unicast to server", "PING:SERVER2:SERVER1:U1:0:data", Base{Destination: "SERVER2", Source: "SERVER1", Routing: Unicast, PacketNumber: 1, HopCount: 0}, false},
+		{"Broadcast to client", "PING:%%EGLL_ATIS:SERVER1:B1:0:data", Base{Destination: "%%EGLL_ATIS", Source: "SERVER1", Routing: Broadcast, PacketNumber: 1, HopCount: 0}, false},
+		{"Unicast to everyone", "PING:*:SERVER1:U1:0:data", Base{}, true},
+		{"Unknown routing", "PING:*:SERVER1:X1:0:data", Base{}, true},
+		{"Missing packet number", "PING:*:SERVER1:B:0:data", Base{}, true},
+		{"Empty packet number", "PING:*:SERVER1::0:data", Base{}, true},
+		{"Invalid packet number", "PING:*:SERVER1:Bx:0:data", Base{}, true},
+		{"Invalid hop count", "PING:*:SERVER1:B1:x:data", Base{}, true},
+		{"Empty client", "PING:%%:SERVER1:U1:0:data", Base{}, true},
+		{"Empty destination", "PING::SERVER1:B1:0:data", Base{}, true},
+		{"Every pilot", "MC:*P:SERVER1:B1:0:EGLL_TWR:Text", Base{Destination: "*P", Source: "SERVER1", Routing: Broadcast, PacketNumber: 1, HopCount: 0}, false},
+		{"Every controller", "MC:*A:SERVER1:B2:1:EGLL_TWR:Text", Base{Destination: "*A", Source: "SERVER1", Routing: Broadcast, PacketNumber: 2, HopCount: 1}, false},
+		{"Frequency", "MC:@18300:SERVER1:B3:2:BAW2:Text", Base{Destination: "@18300", Source: "SERVER1", Routing: Broadcast, PacketNumber: 3, HopCount: 2}, false},
+		{"Unicast to every pilot", "MC:*P:SERVER1:U1:0:EGLL_TWR:Text", Base{}, true},
+		{"Unicast to frequency", "MC:@18300:SERVER1:U1:0:BAW2:Text", Base{}, true},
+		{"Empty frequency", "MC:@:SERVER1:B1:0:BAW2:Text", Base{}, true},
+		{"Invalid frequency", "MC:@EGLL:SERVER1:B1:0:BAW2:Text", Base{}, true},
+		{"Wildcard server", "PING:*S:SERVER1:B1:0:data", Base{}, true},
+		{"Empty source", "PING:*::B1:0:data", Base{}, true},
+		{"Too short", "PING:*:SERVER1:B1:0", Base{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseBase(strings.Split(tt.packet, ":"), 6)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseBase() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseBase() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSerializeRouting(t *testing.T) {
+	tests := []struct {
+		name   string
+		packet Packet
+		want   string
+	}{
+		{"Broadcast", &Sync{Base: Base{Destination: Everyone, Source: "DATA", PacketNumber: 4, HopCount: 1}}, "SYNC:*:DATA:B4:1:"},
+		{"Unicast", &ATISRequest{Base: Base{Destination: ClientDestination("EGLL_ATIS"), Source: "DATA", Routing: Unicast, PacketNumber: 5, HopCount: 1}, From: "DATA"}, "MC:%%EGLL_ATIS:DATA:U5:1:24:DATA:ATIS"},
+		{"Reply", &Pong{Base: Base{Destination: "SERVER1", Source: "DATA", PacketNumber: 6, HopCount: 1}, Data: "data"}, "PONG:SERVER1:DATA:B6:1:data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.packet.Serialize()
+			if got != tt.want {
+				t.Errorf("Serialize() = %q, want %q", got, tt.want)
+			}
+			base, err := parseBase(strings.Split(got, ":"), 5)
+			if err != nil {
+				t.Fatalf("parseBase() error = %v", err)
+			}
+			if !reflect.DeepEqual(base, *tt.packet.base()) {
+				t.Errorf("parseBase() = %+v, want %+v", base, *tt.packet.base())
+			}
+		})
+	}
+}
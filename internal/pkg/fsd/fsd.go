@@ -1,27 +1,16 @@
 package fsd
 
 import (
-	"dataserver/internal/pkg/config"
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
+	"net"
 	"net/textproto"
 	"strings"
 )
 
-// PdCount is a count of the number of packets we have sent to the FSD server.
-var PdCount int
-
 // Connect establishes a connection to the FSD server.
-func Connect() *textproto.Conn {
-	ip, err := config.Cfg.String("fsd.server.ip")
-	if err != nil {
-		log.Fatal("FSD IP not defined.")
-	}
-	port, err := config.Cfg.String("fsd.server.port")
-	if err != nil {
-		log.Fatal("FSD port not defined.")
-	}
-	conn, err := textproto.Dial("tcp", ip+":"+port)
+func Connect(ip string, port string) net.Conn {
+	conn, err := net.Dial("tcp", ip+":"+port)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"ip":    ip,
@@ -32,16 +21,6 @@ func Connect() *textproto.Conn {
 	return conn
 }
 
-// Send formats and sends a new FSD packet to the FSD server.
-func Send(conn *textproto.Conn, message string) error {
-	_, err := conn.Cmd(message)
-	if err != nil {
-		return errors.Wrapf(err, "Failed to send packet to FSD server. %+v", conn)
-	}
-	PdCount++
-	return nil
-}
-
 // ParseMessage splits an FSD message based on the colon delimiter for further handling.
 func ParseMessage(message string) []string {
 	split := strings.Split(message, ":")
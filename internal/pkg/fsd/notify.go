@@ -1,9 +1,6 @@
 package fsd
 
-import (
-	"strconv"
-	"strings"
-)
+import "strconv"
 
 // Notify NOTIFY
 type Notify struct {
@@ -20,17 +17,7 @@ type Notify struct {
 
 // Serialize converts a struct into an FSD packet
 func (n Notify) Serialize() string {
-	msg := strings.Builder{}
-	msg.WriteString("NOTIFY")
-	msg.WriteString(":")
-	msg.WriteString(n.Destination)
-	msg.WriteString(":")
-	msg.WriteString(n.Source)
-	msg.WriteString(":")
-	msg.WriteString("B")
-	msg.WriteString(strconv.Itoa(n.PacketNumber))
-	msg.WriteString(":")
-	msg.WriteString(strconv.Itoa(n.HopCount))
+	msg := n.header("NOTIFY")
 	msg.WriteString(":")
 	msg.WriteString(strconv.Itoa(n.FeedFlag))
 	msg.WriteString(":")
@@ -21,62 +21,50 @@ type PilotData struct {
 
 // DeserializePilotData maps an array of strings to a PilotData struct
 func DeserializePilotData(fields []string) (PilotData, error) {
-	if len(fields) >= 14 {
-		packetNumber, err := strconv.Atoi(fields[3][1:])
-		if err != nil {
-			return PilotData{}, errors.Wrapf(err, "Failed to parse packet number. %v", reassemble(fields))
-		}
-		hopCount, err := strconv.Atoi(fields[4])
-		if err != nil {
-			return PilotData{}, errors.Wrapf(err, "Failed to parse hop count. %v", reassemble(fields))
-		}
-		transponder, err := strconv.Atoi(fields[7])
-		if err != nil {
-			return PilotData{}, errors.Wrapf(err, "Failed to parse transponder. %v", reassemble(fields))
-		}
-		rating, err := strconv.Atoi(fields[8])
-		if err != nil {
-			return PilotData{}, errors.Wrapf(err, "Failed to parse rating. %v", reassemble(fields))
-		}
-		latitude, err := strconv.ParseFloat(fields[9], 64)
-		if err != nil {
-			return PilotData{}, errors.Wrapf(err, "Failed to parse latitude. %v", reassemble(fields))
-		}
-		longitude, err := strconv.ParseFloat(fields[10], 64)
-		if err != nil {
-			return PilotData{}, errors.Wrapf(err, "Failed to parse longitude. %v", reassemble(fields))
-		}
-		altitude, err := strconv.Atoi(fields[11])
-		if err != nil {
-			return PilotData{}, errors.Wrapf(err, "Failed to parse altitude. %v", reassemble(fields))
-		}
-		speed, err := strconv.Atoi(fields[12])
-		if err != nil {
-			return PilotData{}, errors.Wrapf(err, "Failed to parse speed. %v", reassemble(fields))
-		}
-		heading, err := getHeading(fields[13])
-		if err != nil {
-			return PilotData{}, errors.Wrapf(err, "Failed to parse heading. %v", reassemble(fields))
-		}
-		return PilotData{
-			Base: Base{
-				Destination:  fields[1],
-				Source:       fields[2],
-				PacketNumber: packetNumber,
-				HopCount:     hopCount,
-			},
-			IdentFlag:   fields[5],
-			Callsign:    fields[6],
-			Transponder: transponder,
-			Rating:      rating,
-			Latitude:    latitude,
-			Longitude:   longitude,
-			Altitude:    altitude,
-			GroundSpeed: speed,
-			Heading:     heading,
-		}, nil
+	base, err := parseBase(fields, 14)
+	if err != nil {
+		return PilotData{}, err
+	}
+	transponder, err := strconv.Atoi(fields[7])
+	if err != nil {
+		return PilotData{}, errors.Wrapf(err, "Failed to parse transponder. %v", reassemble(fields))
+	}
+	rating, err := strconv.Atoi(fields[8])
+	if err != nil {
+		return PilotData{}, errors.Wrapf(err, "Failed to parse rating. %v", reassemble(fields))
+	}
+	latitude, err := strconv.ParseFloat(fields[9], 64)
+	if err != nil {
+		return PilotData{}, errors.Wrapf(err, "Failed to parse latitude. %v", reassemble(fields))
+	}
+	longitude, err := strconv.ParseFloat(fields[10], 64)
+	if err != nil {
+		return PilotData{}, errors.Wrapf(err, "Failed to parse longitude. %v", reassemble(fields))
+	}
+	altitude, err := strconv.Atoi(fields[11])
+	if err != nil {
+		return PilotData{}, errors.Wrapf(err, "Failed to parse altitude. %v", reassemble(fields))
+	}
+	speed, err := strconv.Atoi(fields[12])
+	if err != nil {
+		return PilotData{}, errors.Wrapf(err, "Failed to parse speed. %v", reassemble(fields))
+	}
+	heading, err := getHeading(fields[13])
+	if err != nil {
+		return PilotData{}, errors.Wrapf(err, "Failed to parse heading. %v", reassemble(fields))
 	}
-	return PilotData{}, errors.Errorf("Invalid packet. %v", reassemble(fields))
+	return PilotData{
+		Base:        base,
+		IdentFlag:   fields[5],
+		Callsign:    fields[6],
+		Transponder: transponder,
+		Rating:      rating,
+		Latitude:    latitude,
+		Longitude:   longitude,
+		Altitude:    altitude,
+		GroundSpeed: speed,
+		Heading:     heading,
+	}, nil
 }
 
 // getHeading parses the PBH FSD value to extract the heading
@@ -1,10 +1,5 @@
 package fsd
 
-import (
-	"strconv"
-	"strings"
-)
-
 // Pong PONG
 type Pong struct {
 	Base
@@ -13,17 +8,7 @@ type Pong struct {
 
 // Serialize converts a struct into an FSD packet
 func (p Pong) Serialize() string {
-	msg := strings.Builder{}
-	msg.WriteString("PONG")
-	msg.WriteString(":")
-	msg.WriteString(p.Destination)
-	msg.WriteString(":")
-	msg.WriteString(p.Source)
-	msg.WriteString(":")
-	msg.WriteString("B")
-	msg.WriteString(strconv.Itoa(p.PacketNumber))
-	msg.WriteString(":")
-	msg.WriteString(strconv.Itoa(p.HopCount))
+	msg := p.header("PONG")
 	msg.WriteString(":")
 	msg.WriteString(p.Data)
 	return msg.String()
@@ -0,0 +1,13 @@
+package fsd
+
+// Sync SYNC
+type Sync struct {
+	Base
+}
+
+// Serialize converts a struct into an FSD packet
+func (s Sync) Serialize() string {
+	msg := s.header("SYNC")
+	msg.WriteString(":")
+	return msg.String()
+}
@@ -0,0 +1,213 @@
+package fsd
+
+import (
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	log "github.com/sirupsen/logrus"
+	"io"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Errors returned when a packet can't be queued.
+var (
+	ErrQueueFull    = errors.New("FSD send queue full.")
+	ErrWriterClosed = errors.New("FSD writer closed.")
+)
+
+var (
+	packetsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "dataserver_packets_sent",
+		Help: "The total number of packets sent to FSD.",
+	}, []string{"packet"})
+
+	packetsDropped = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "dataserver_packets_dropped",
+		Help: "The total number of packets dropped because the send queue was full.",
+	})
+
+	packetWriteErrors = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "dataserver_packet_write_errors",
+		Help: "The total number of packets which failed to be written to FSD.",
+	})
+
+	sendQueueDepth = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "dataserver_send_queue_depth",
+		Help: "The number of packets waiting to be sent to FSD.",
+	})
+)
+
+// WriterOptions configures how packets are sent.
+type WriterOptions struct {
+	// Queue is the number of packets waiting to be sent before more are dropped.
+	Queue int
+	// Timeout is how long writing a packet may take.
+	Timeout time.Duration
+	// Rate is the number of packets sent per second, unlimited if zero.
+	Rate int
+	// Burst is the number of packets which may be sent at once after a quiet period.
+	Burst int
+}
+
+// Writer sends packets to an FSD connection from a single goroutine, numbering them in the order they are written.
+type Writer struct {
+	conn    net.Conn
+	options WriterOptions
+	queue   chan Packet
+	sent    int64
+	closed  bool
+	mutex   sync.RWMutex
+	done    chan struct{}
+}
+
+// NewWriter starts sending packets queued for the connection.
+func NewWriter(conn net.Conn, options WriterOptions) *Writer {
+	w := &Writer{
+		conn:    conn,
+		options: options,
+		queue:   make(chan Packet, options.Queue),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Send queues a packet, which is numbered when it is written, after checking it can be routed.
+func (w *Writer) Send(packet Packet) error {
+	if err := packet.base().Validate(); err != nil {
+		return errors.Wrapf(err, "Can't route %v", packet.Serialize())
+	}
+	w.mutex.RLock()
+	defer w.mutex.RUnlock()
+	if w.closed {
+		return errors.WithStack(ErrWriterClosed)
+	}
+	select {
+	case w.queue <- packet:
+		sendQueueDepth.Set(float64(len(w.queue)))
+		return nil
+	default:
+		packetsDropped.Inc()
+		return errors.WithStack(ErrQueueFull)
+	}
+}
+
+// Sent returns the number of packets written so far, which is also the number of the next one.
+func (w *Writer) Sent() int {
+	return int(atomic.LoadInt64(&w.sent))
+}
+
+// Close writes every packet still queued and stops the writer.
+func (w *Writer) Close() {
+	w.mutex.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mutex.Unlock()
+	<-w.done
+}
+
+// fail stops accepting packets and closes the connection, so reads from it fail too
+func (w *Writer) fail() {
+	w.mutex.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mutex.Unlock()
+	err := w.conn.Close()
+	if err != nil {
+		log.WithField("error", err).Error("Failed to close FSD connection.")
+	}
+}
+
+// run writes queued packets until the writer is closed.
+// A failed write leaves the connection in an unknown state, so it is closed and the rest of the queue is discarded.
+func (w *Writer) run() {
+	defer close(w.done)
+	limit := newLimiter(w.options.Rate, w.options.Burst)
+	failed := false
+	for packet := range w.queue {
+		sendQueueDepth.Set(float64(len(w.queue)))
+		if failed {
+			continue
+		}
+		limit.wait()
+		packet.base().PacketNumber = w.Sent()
+		message := packet.Serialize()
+		err := w.write(message)
+		if err != nil {
+			packetWriteErrors.Inc()
+			log.WithFields(log.Fields{
+				"packet": message,
+				"error":  err,
+			}).Error("Failed to send packet to FSD server, closing the connection.")
+			failed = true
+			w.fail()
+			continue
+		}
+		atomic.AddInt64(&w.sent, 1)
+		packetsSent.With(prometheus.Labels{"packet": packetType(message)}).Inc()
+		log.WithField("packet", message).Debug("Successfully sent packet to FSD server.")
+	}
+}
+
+// write sends a single packet within the write timeout
+func (w *Writer) write(message string) error {
+	if w.options.Timeout > 0 {
+		err := w.conn.SetWriteDeadline(time.Now().Add(w.options.Timeout))
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	_, err := io.WriteString(w.conn, message+"\r\n")
+	return errors.WithStack(err)
+}
+
+// packetType is the command a packet starts with
+func packetType(message string) string {
+	return strings.SplitN(message, ":", 2)[0]
+}
+
+// limiter spaces packets out to a rate, allowing bursts after quiet periods
+type limiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// newLimiter creates a limiter which starts with a full burst
+func newLimiter(rate int, burst int) *limiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &limiter{
+		rate:   float64(rate),
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until another packet may be sent
+func (l *limiter) wait() {
+	if l.rate <= 0 {
+		return
+	}
+	now := time.Now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens < 1 {
+		time.Sleep(time.Duration((1 - l.tokens) / l.rate * float64(time.Second)))
+		l.last = time.Now()
+		l.tokens = 1
+	}
+	l.tokens--
+}
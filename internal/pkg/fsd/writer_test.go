@@ -0,0 +1,107 @@
+package fsd
+
+import (
+	"bufio"
+	"github.com/pkg/errors"
+	"io"
+	"net"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriterSend(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	writer := NewWriter(client, WriterOptions{Queue: 10, Timeout: time.Second})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := writer.Send(&Sync{Base: Base{Destination: "*", Source: "DATA", HopCount: 1}})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	reader := bufio.NewReader(server)
+	for _, want := range []string{"SYNC:*:DATA:B0:1:\r\n", "SYNC:*:DATA:B1:1:\r\n", "SYNC:*:DATA:B2:1:\r\n"} {
+		got, err := reader.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("Send() wrote %q, want %q", got, want)
+		}
+	}
+	writer.Close()
+	if writer.Sent() != 3 {
+		t.Errorf("Sent() = %d, want 3", writer.Sent())
+	}
+	if err := writer.Send(&Sync{Base: Base{Destination: "*", Source: "DATA"}}); errors.Cause(err) != ErrWriterClosed {
+		t.Errorf("Send() after Close() error = %v, want %v", err, ErrWriterClosed)
+	}
+}
+
+func TestWriterQueueFull(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	defer client.Close()
+	writer := NewWriter(client, WriterOptions{Queue: 1, Timeout: time.Second})
+
+	// Nothing reads the pipe, so the first packet blocks the writer and the second fills the queue
+	var err error
+	for i := 0; i < 3 && err == nil; i++ {
+		err = writer.Send(&Sync{Base: Base{Destination: "*", Source: "DATA"}})
+	}
+	if errors.Cause(err) != ErrQueueFull {
+		t.Errorf("Send() error = %v, want %v", err, ErrQueueFull)
+	}
+}
+
+func TestWriterWriteError(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	writer := NewWriter(client, WriterOptions{Queue: 10, Timeout: 10 * time.Millisecond})
+
+	// Nothing reads the pipe, so the first write times out
+	for i := 0; i < 3; i++ {
+		if err := writer.Send(&Sync{Base: Base{Destination: "*", Source: "DATA"}}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writer.Close()
+	server.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := server.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Read() error = %v, want the connection closed", err)
+	}
+	if writer.Sent() != 0 {
+		t.Errorf("Sent() = %d, want 0", writer.Sent())
+	}
+}
+
+func TestWriterSendUnroutable(t *testing.T) {
+	client, server := net.Pipe()
+	defer server.Close()
+	writer := NewWriter(client, WriterOptions{Queue: 1, Timeout: time.Second})
+	defer writer.Close()
+
+	if err := writer.Send(&Sync{Base: Base{Destination: "*", Source: "DATA", Routing: Unicast}}); err == nil {
+		t.Error("Send() of a unicast packet to everyone succeeded, want an error")
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	limit := newLimiter(100, 2)
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		limit.wait()
+	}
+	if elapsed := time.Since(start); elapsed < 15*time.Millisecond {
+		t.Errorf("wait() let 4 packets through in %v, want at least 20ms after a burst of 2", elapsed)
+	}
+}
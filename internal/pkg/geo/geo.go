@@ -0,0 +1,304 @@
+package geo
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// EarthRadius is the mean radius of the earth in nautical miles.
+const EarthRadius = 3440.065
+
+// maxDistance is half the circumference of the earth, the furthest two points can be apart.
+const maxDistance = math.Pi * EarthRadius
+
+// Point is a position on the earth in decimal degrees.
+type Point struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// BoundingBox is an area between two latitudes and two longitudes.
+// West may be greater than east for boxes crossing the antimeridian.
+type BoundingBox struct {
+	South float64
+	West  float64
+	North float64
+	East  float64
+}
+
+// Result is a key found by a query with its distance from the query point.
+type Result struct {
+	Key      string
+	Point    Point
+	Distance float64
+}
+
+// Index is a grid of cells used to find keyed points by area.
+type Index struct {
+	Mutex    *sync.RWMutex
+	cellSize float64
+	cells    map[cell]map[string]Point
+	points   map[string]Point
+}
+
+// cell identifies a square of the grid
+type cell struct {
+	latitude  int
+	longitude int
+}
+
+// Contains checks if a point is inside the bounding box.
+func (b BoundingBox) Contains(p Point) bool {
+	if p.Latitude < b.South || p.Latitude > b.North {
+		return false
+	}
+	if b.West <= b.East {
+		return p.Longitude >= b.West && p.Longitude <= b.East
+	}
+	return p.Longitude >= b.West || p.Longitude <= b.East
+}
+
+// Distance calculates the great circle distance between two points in nautical miles.
+func Distance(a Point, b Point) float64 {
+	lat1 := a.Latitude * math.Pi / 180
+	lat2 := b.Latitude * math.Pi / 180
+	dLat := lat2 - lat1
+	dLon := (b.Longitude - a.Longitude) * math.Pi / 180
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadius * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Destination calculates the point reached by travelling a distance in nautical miles along a great circle
+// from a starting point on an initial bearing in degrees.
+// The longitude is not normalized so shapes crossing the antimeridian stay continuous.
+func Destination(start Point, bearing float64, distance float64) Point {
+	lat1 := start.Latitude * math.Pi / 180
+	lon1 := start.Longitude * math.Pi / 180
+	theta := bearing * math.Pi / 180
+	delta := distance / EarthRadius
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta))
+	lon2 := lon1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*math.Sin(lat2))
+	return Point{
+		Latitude:  lat2 * 180 / math.Pi,
+		Longitude: lon2 * 180 / math.Pi,
+	}
+}
+
+// Circle approximates a circle with a radius in nautical miles as a closed ring of points.
+// The ring runs counter-clockwise, as GeoJSON requires of a polygon's exterior.
+func Circle(center Point, radius float64, segments int) []Point {
+	ring := make([]Point, 0, segments+1)
+	for i := 0; i < segments; i++ {
+		ring = append(ring, Destination(center, -float64(i)*360/float64(segments), radius))
+	}
+	return append(ring, ring[0])
+}
+
+// NewIndex creates an empty index with cells of the given size in degrees.
+func NewIndex(cellSize float64) *Index {
+	return &Index{
+		Mutex:    &sync.RWMutex{},
+		cellSize: cellSize,
+		cells:    map[cell]map[string]Point{},
+		points:   map[string]Point{},
+	}
+}
+
+// Update moves a key to a new point, adding it if it is not yet indexed.
+func (i *Index) Update(key string, p Point) {
+	if i == nil {
+		return
+	}
+	i.Mutex.Lock()
+	defer i.Mutex.Unlock()
+	i.remove(key)
+	c := i.cellOf(p)
+	if i.cells[c] == nil {
+		i.cells[c] = map[string]Point{}
+	}
+	i.cells[c][key] = p
+	i.points[key] = p
+}
+
+// Remove takes a key out of the index.
+func (i *Index) Remove(key string) {
+	if i == nil {
+		return
+	}
+	i.Mutex.Lock()
+	defer i.Mutex.Unlock()
+	i.remove(key)
+}
+
+// Len is the number of indexed keys.
+func (i *Index) Len() int {
+	i.Mutex.RLock()
+	defer i.Mutex.RUnlock()
+	return len(i.points)
+}
+
+// InBoundingBox finds every key inside a bounding box.
+func (i *Index) InBoundingBox(b BoundingBox) []Result {
+	i.Mutex.RLock()
+	defer i.Mutex.RUnlock()
+	var results []Result
+	i.scan(b, func(key string, p Point) {
+		if b.Contains(p) {
+			results = append(results, Result{Key: key, Point: p})
+		}
+	})
+	sort.Slice(results, func(a, b int) bool {
+		return results[a].Key < results[b].Key
+	})
+	return results
+}
+
+// WithinRadius finds every key within a radius in nautical miles of a point, nearest first.
+func (i *Index) WithinRadius(center Point, radius float64) []Result {
+	i.Mutex.RLock()
+	defer i.Mutex.RUnlock()
+	return i.withinRadius(center, radius)
+}
+
+// Nearest finds the k keys nearest to a point, nearest first.
+func (i *Index) Nearest(center Point, k int) []Result {
+	i.Mutex.RLock()
+	defer i.Mutex.RUnlock()
+	if k <= 0 {
+		return nil
+	}
+	// Widen the search until enough keys are found or the whole earth has been covered
+	radius := 60.0
+	for {
+		results := i.withinRadius(center, radius)
+		if len(results) >= k || radius >= maxDistance {
+			if len(results) > k {
+				results = results[:k]
+			}
+			return results
+		}
+		radius *= 2
+	}
+}
+
+// withinRadius finds keys within the radius, the caller must hold the lock
+func (i *Index) withinRadius(center Point, radius float64) []Result {
+	var results []Result
+	i.scan(boundingBoxAround(center, radius), func(key string, p Point) {
+		distance := Distance(center, p)
+		if distance <= radius {
+			results = append(results, Result{Key: key, Point: p, Distance: distance})
+		}
+	})
+	sort.Slice(results, func(a, b int) bool {
+		if results[a].Distance == results[b].Distance {
+			return results[a].Key < results[b].Key
+		}
+		return results[a].Distance < results[b].Distance
+	})
+	return results
+}
+
+// boundingBoxAround finds a bounding box containing every point within the radius
+func boundingBoxAround(center Point, radius float64) BoundingBox {
+	degrees := radius / EarthRadius * 180 / math.Pi
+	south := center.Latitude - degrees
+	north := center.Latitude + degrees
+	if south <= -90 || north >= 90 {
+		return BoundingBox{South: math.Max(south, -90), West: -180, North: math.Min(north, 90), East: 180}
+	}
+	// Longitude degrees shrink towards the poles so the widest part of the circle is used
+	spread := degrees / math.Cos(math.Max(math.Abs(south), math.Abs(north))*math.Pi/180)
+	if spread >= 180 {
+		return BoundingBox{South: south, West: -180, North: north, East: 180}
+	}
+	return BoundingBox{
+		South: south,
+		West:  normalizeLongitude(center.Longitude - spread),
+		North: north,
+		East:  normalizeLongitude(center.Longitude + spread),
+	}
+}
+
+// normalizeLongitude wraps a longitude into the range -180 to 180
+func normalizeLongitude(longitude float64) float64 {
+	if longitude < -180 || longitude > 180 {
+		longitude = math.Mod(longitude+180, 360)
+		if longitude < 0 {
+			longitude += 360
+		}
+		longitude -= 180
+	}
+	return longitude
+}
+
+// scan calls fn for every key in the cells overlapping the bounding box.
+// The box is clamped to the earth so a huge or malformed one can't hold the lock for long.
+func (i *Index) scan(b BoundingBox, fn func(key string, p Point)) {
+	b.South = math.Max(b.South, -90)
+	b.North = math.Min(b.North, 90)
+	if !(b.South <= b.North) {
+		return
+	}
+	// Boxes spanning every longitude, or whose span isn't a number, scan every column
+	if !(b.East-b.West < 360) {
+		b.West, b.East = -180, 180
+	}
+	b.West = normalizeLongitude(b.West)
+	b.East = normalizeLongitude(b.East)
+	first, last := i.longitudeCells()
+	columns := last - first + 1
+	south := i.cellOf(Point{Latitude: b.South, Longitude: b.West})
+	north := i.cellOf(Point{Latitude: b.North, Longitude: b.East})
+	width := north.longitude - south.longitude + 1
+	if b.West > b.East {
+		width += columns
+	}
+	if width > columns {
+		width = columns
+	}
+	for latitude := south.latitude; latitude <= north.latitude; latitude++ {
+		for offset := 0; offset < width; offset++ {
+			longitude := south.longitude + offset
+			if longitude > last {
+				longitude -= columns
+			}
+			for key, p := range i.cells[cell{latitude: latitude, longitude: longitude}] {
+				fn(key, p)
+			}
+		}
+	}
+}
+
+// longitudeCells finds the first and last columns of the grid
+func (i *Index) longitudeCells() (int, int) {
+	return int(math.Floor(-180 / i.cellSize)), int(math.Ceil(180/i.cellSize)) - 1
+}
+
+// cellOf finds the cell containing a point, keeping the antimeridian in the last column
+func (i *Index) cellOf(p Point) cell {
+	_, last := i.longitudeCells()
+	longitude := int(math.Floor(normalizeLongitude(p.Longitude) / i.cellSize))
+	if longitude > last {
+		longitude = last
+	}
+	return cell{
+		latitude:  int(math.Floor(p.Latitude / i.cellSize)),
+		longitude: longitude,
+	}
+}
+
+// remove takes a key out of the index, the caller must hold the lock
+func (i *Index) remove(key string) {
+	p, ok := i.points[key]
+	if !ok {
+		return
+	}
+	c := i.cellOf(p)
+	delete(i.cells[c], key)
+	if len(i.cells[c]) == 0 {
+		delete(i.cells, c)
+	}
+	delete(i.points, key)
+}
@@ -0,0 +1,93 @@
+package geo
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a    Point
+		b    Point
+		want float64
+	}{
+		{"Same point", Point{51.4775, -0.4614}, Point{51.4775, -0.4614}, 0},
+		{"EGLL to KJFK", Point{51.4775, -0.4614}, Point{40.6398, -73.7789}, 2991},
+		{"Across the antimeridian", Point{0, 179.5}, Point{0, -179.5}, 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Distance(tt.a, tt.b); math.Abs(got-tt.want) > 1 {
+				t.Errorf("Distance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCircle(t *testing.T) {
+	center := Point{51.4775, -0.4614}
+	ring := Circle(center, 50, 16)
+	if len(ring) != 17 {
+		t.Fatalf("Circle() len = %d, want 17", len(ring))
+	}
+	if ring[0] != ring[16] {
+		t.Errorf("Circle() ring is not closed")
+	}
+	for _, v := range ring {
+		if got := Distance(center, v); math.Abs(got-50) > 0.01 {
+			t.Errorf("Circle() point %v is %v from center, want 50", v, got)
+		}
+	}
+}
+
+func TestIndex(t *testing.T) {
+	index := NewIndex(1)
+	index.Update("EGLL_TWR", Point{51.4775, -0.4614})
+	index.Update("EGKK_TWR", Point{51.1481, -0.1903})
+	index.Update("LFPG_TWR", Point{49.0097, 2.5479})
+	index.Update("KJFK_TWR", Point{40.6398, -73.7789})
+	index.Update("NZAA_TWR", Point{-37.0082, 174.7850})
+	index.Update("PASY_TWR", Point{52.7123, 174.1136})
+	index.Update("UHMA_TWR", Point{64.7349, 177.7416})
+	index.Update("PAOM_TWR", Point{64.5122, -165.4453})
+	index.Update("MOVED", Point{0, 0})
+	index.Update("MOVED", Point{51.5, -0.1})
+	index.Update("REMOVED", Point{51.5, -0.2})
+	index.Remove("REMOVED")
+
+	keys := func(results []Result) []string {
+		var keys []string
+		for _, v := range results {
+			keys = append(keys, v.Key)
+		}
+		return keys
+	}
+
+	tests := []struct {
+		name string
+		got  []Result
+		want []string
+	}{
+		{"Bounding box", index.InBoundingBox(BoundingBox{South: 50, West: -1, North: 52, East: 0}), []string{"EGKK_TWR", "EGLL_TWR", "MOVED"}},
+		{"Bounding box across the antimeridian", index.InBoundingBox(BoundingBox{South: 60, West: 170, North: 70, East: -160}), []string{"PAOM_TWR", "UHMA_TWR"}},
+		{"Bounding box beyond the earth", index.InBoundingBox(BoundingBox{South: -1e300, West: -1e300, North: 1e300, East: 1e300}), []string{"EGKK_TWR", "EGLL_TWR", "KJFK_TWR", "LFPG_TWR", "MOVED", "NZAA_TWR", "PAOM_TWR", "PASY_TWR", "UHMA_TWR"}},
+		{"Infinite bounding box", index.InBoundingBox(BoundingBox{South: math.Inf(-1), West: math.Inf(-1), North: math.Inf(1), East: math.Inf(1)}), []string{"EGKK_TWR", "EGLL_TWR", "KJFK_TWR", "LFPG_TWR", "MOVED", "NZAA_TWR", "PAOM_TWR", "PASY_TWR", "UHMA_TWR"}},
+		{"Bounding box of NaN", index.InBoundingBox(BoundingBox{South: math.NaN(), West: math.NaN(), North: math.NaN(), East: math.NaN()}), nil},
+		{"Within radius", index.WithinRadius(Point{51.4775, -0.4614}, 30), []string{"EGLL_TWR", "MOVED", "EGKK_TWR"}},
+		{"Within radius across the antimeridian", index.WithinRadius(Point{64.5, 179.9}, 400), []string{"UHMA_TWR", "PAOM_TWR"}},
+		{"Nearest", index.Nearest(Point{49, 2.5}, 2), []string{"LFPG_TWR", "EGKK_TWR"}},
+		{"Nearest beyond the first search", index.Nearest(Point{-37, 174.8}, 2), []string{"NZAA_TWR", "PASY_TWR"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := keys(tt.got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := index.Len(); got != 9 {
+		t.Errorf("Len() = %v, want %v", got, 9)
+	}
+}
@@ -0,0 +1,163 @@
+package logbook
+
+import (
+	"database/sql"
+	"github.com/pkg/errors"
+	"time"
+
+	// Registers the sqlite3 driver with database/sql
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// Logbook stores completed sessions in a SQLite database.
+type Logbook struct {
+	DB *sql.DB
+}
+
+// Entry is a single completed session.
+type Entry struct {
+	CID        int
+	Callsign   string
+	Type       string
+	Server     string
+	Rating     int
+	LogonTime  time.Time
+	LogoffTime time.Time
+	Departure  string
+	Arrival    string
+}
+
+// Hours is the total time a member has spent connected as one client type.
+type Hours struct {
+	Type     string
+	Sessions int
+	Duration time.Duration
+}
+
+// Activity is the total time spent connected under one callsign.
+type Activity struct {
+	Callsign  string
+	Sessions  int
+	Duration  time.Duration
+	LastLogon time.Time
+}
+
+const schema = `
+CREATE TABLE IF NOT EXISTS sessions (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	cid INTEGER NOT NULL,
+	callsign TEXT NOT NULL,
+	type TEXT NOT NULL,
+	server TEXT NOT NULL,
+	rating INTEGER NOT NULL,
+	logon_time INTEGER NOT NULL,
+	logoff_time INTEGER NOT NULL,
+	departure TEXT NOT NULL DEFAULT '',
+	arrival TEXT NOT NULL DEFAULT ''
+);
+CREATE INDEX IF NOT EXISTS sessions_cid ON sessions (cid);
+CREATE INDEX IF NOT EXISTS sessions_callsign ON sessions (callsign);
+`
+
+// Open opens the logbook database, creating it if it does not exist.
+func Open(path string) (*Logbook, error) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to open logbook. %v", path)
+	}
+	_, err = db.Exec(schema)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to create logbook schema. %v", path)
+	}
+	return &Logbook{DB: db}, nil
+}
+
+// Close closes the logbook database.
+func (l *Logbook) Close() error {
+	if l == nil {
+		return nil
+	}
+	return errors.WithStack(l.DB.Close())
+}
+
+// Record writes a completed session to the logbook.
+func (l *Logbook) Record(entry Entry) error {
+	if l == nil {
+		return nil
+	}
+	_, err := l.DB.Exec(
+		"INSERT INTO sessions (cid, callsign, type, server, rating, logon_time, logoff_time, departure, arrival) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+		entry.CID,
+		entry.Callsign,
+		entry.Type,
+		entry.Server,
+		entry.Rating,
+		entry.LogonTime.Unix(),
+		entry.LogoffTime.Unix(),
+		entry.Departure,
+		entry.Arrival,
+	)
+	if err != nil {
+		return errors.Wrapf(err, "Failed to record session. %+v", entry)
+	}
+	return nil
+}
+
+// Purge deletes every session belonging to a CID.
+func (l *Logbook) Purge(cid int) (int64, error) {
+	if l == nil {
+		return 0, nil
+	}
+	result, err := l.DB.Exec("DELETE FROM sessions WHERE cid = ?", cid)
+	if err != nil {
+		return 0, errors.Wrapf(err, "Failed to purge sessions. %v", cid)
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return count, nil
+}
+
+// HoursByCID totals a member's connected time for each client type.
+func (l *Logbook) HoursByCID(cid int) ([]Hours, error) {
+	rows, err := l.DB.Query("SELECT type, COUNT(*), SUM(logoff_time - logon_time) FROM sessions WHERE cid = ? GROUP BY type ORDER BY type", cid)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to query hours. %v", cid)
+	}
+	defer rows.Close()
+	var hours []Hours
+	for rows.Next() {
+		var h Hours
+		var seconds int64
+		err = rows.Scan(&h.Type, &h.Sessions, &seconds)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		h.Duration = time.Duration(seconds) * time.Second
+		hours = append(hours, h)
+	}
+	return hours, errors.WithStack(rows.Err())
+}
+
+// ControllerActivity totals the connected time of controller callsigns starting with a prefix.
+func (l *Logbook) ControllerActivity(prefix string) ([]Activity, error) {
+	rows, err := l.DB.Query("SELECT callsign, COUNT(*), SUM(logoff_time - logon_time), MAX(logon_time) FROM sessions WHERE type = 'controller' AND substr(callsign, 1, length(?)) = ? GROUP BY callsign ORDER BY callsign", prefix, prefix)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Failed to query controller activity. %v", prefix)
+	}
+	defer rows.Close()
+	var activity []Activity
+	for rows.Next() {
+		var a Activity
+		var seconds, lastLogon int64
+		err = rows.Scan(&a.Callsign, &a.Sessions, &seconds, &lastLogon)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		a.Duration = time.Duration(seconds) * time.Second
+		a.LastLogon = time.Unix(lastLogon, 0).UTC()
+		activity = append(activity, a)
+	}
+	return activity, errors.WithStack(rows.Err())
+}
@@ -0,0 +1,71 @@
+package logbook
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLogbook(t *testing.T) {
+	directory, err := ioutil.TempDir("", "logbook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(directory)
+	logbook, err := Open(filepath.Join(directory, "logbook.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logbook.Close()
+
+	logon := time.Date(2019, 11, 1, 12, 0, 0, 0, time.UTC)
+	entries := []Entry{
+		{CID: 1000001, Callsign: "EGLL_TWR", Type: "controller", Server: "UK", Rating: 3, LogonTime: logon, LogoffTime: logon.Add(2 * time.Hour)},
+		{CID: 1000001, Callsign: "EGLL_TWR", Type: "controller", Server: "UK", Rating: 3, LogonTime: logon.Add(24 * time.Hour), LogoffTime: logon.Add(25 * time.Hour)},
+		{CID: 1000001, Callsign: "BAW123", Type: "pilot", Server: "UK", Rating: 1, LogonTime: logon, LogoffTime: logon.Add(90 * time.Minute), Departure: "EGLL", Arrival: "KJFK"},
+		{CID: 1000002, Callsign: "EGKK_APP", Type: "controller", Server: "UK", Rating: 5, LogonTime: logon, LogoffTime: logon.Add(time.Hour)},
+		{CID: 1000003, Callsign: "LFPG_TWR", Type: "controller", Server: "EU", Rating: 3, LogonTime: logon, LogoffTime: logon.Add(time.Hour)},
+		{CID: 1000004, Callsign: "EGLL_OBS", Type: "observer", Server: "UK", Rating: 1, LogonTime: logon, LogoffTime: logon.Add(time.Hour)},
+		{CID: 1000005, Callsign: "EGKK_APP", Type: "supervisor", Server: "UK", Rating: 11, LogonTime: logon, LogoffTime: logon.Add(time.Hour)},
+	}
+	for _, v := range entries {
+		if err := logbook.Record(v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	hours, err := logbook.HoursByCID(1000001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantHours := []Hours{
+		{Type: "controller", Sessions: 2, Duration: 3 * time.Hour},
+		{Type: "pilot", Sessions: 1, Duration: 90 * time.Minute},
+	}
+	if !reflect.DeepEqual(hours, wantHours) {
+		t.Errorf("HoursByCID() got = %v, want %v", hours, wantHours)
+	}
+
+	activity, err := logbook.ControllerActivity("EG")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantActivity := []Activity{
+		{Callsign: "EGKK_APP", Sessions: 1, Duration: time.Hour, LastLogon: logon},
+		{Callsign: "EGLL_TWR", Sessions: 2, Duration: 3 * time.Hour, LastLogon: logon.Add(24 * time.Hour)},
+	}
+	if !reflect.DeepEqual(activity, wantActivity) {
+		t.Errorf("ControllerActivity() got = %v, want %v", activity, wantActivity)
+	}
+
+	count, err := logbook.Purge(1000001)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("Purge() got = %v, want %v", count, 3)
+	}
+}
@@ -0,0 +1,160 @@
+package rpc
+
+import (
+	"dataserver/internal/pkg/dataserver"
+	"dataserver/internal/pkg/rpc/pb"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/golang/protobuf/ptypes/timestamp"
+	"time"
+)
+
+// toTimestamp converts a time, leaving unset times empty
+func toTimestamp(t time.Time) *timestamp.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	ts, err := ptypes.TimestampProto(t)
+	if err != nil {
+		return nil
+	}
+	return ts
+}
+
+// toMember converts a member's personal data
+func toMember(m dataserver.MemberData) *pb.MemberData {
+	return &pb.MemberData{
+		Cid:  int32(m.CID),
+		Name: m.Name,
+	}
+}
+
+// toFlightPlan converts a filed flight plan
+func toFlightPlan(f dataserver.FlightPlan) *pb.FlightPlan {
+	return &pb.FlightPlan{
+		FlightRules: f.FlightRules,
+		Aircraft:    f.Aircraft,
+		CruiseSpeed: f.CruiseSpeed,
+		Departure:   f.Departure,
+		Arrival:     f.Arrival,
+		Altitude:    f.Altitude,
+		Alternate:   f.Alternate,
+		Route:       f.Route,
+		Time: &pb.FlightPlanTime{
+			Departure:      f.Time.Departure,
+			HoursEnroute:   f.Time.HoursEnroute,
+			MinutesEnroute: f.Time.MinutesEnroute,
+			HoursFuel:      f.Time.HoursFuel,
+			MinutesFuel:    f.Time.MinutesFuel,
+		},
+		Remarks: f.Remarks,
+	}
+}
+
+// toPilot converts a pilot
+func toPilot(p dataserver.Pilot) *pb.Pilot {
+	return &pb.Pilot{
+		Server:      p.Server,
+		Callsign:    p.Callsign,
+		Member:      toMember(p.Member),
+		Rating:      int32(p.Rating),
+		Latitude:    p.Latitude,
+		Longitude:   p.Longitude,
+		Altitude:    int32(p.Altitude),
+		Speed:       int32(p.Speed),
+		Heading:     int32(p.Heading),
+		Plan:        toFlightPlan(p.FlightPlan),
+		LogonTime:   toTimestamp(p.LogonTime),
+		LastUpdated: toTimestamp(p.LastUpdated),
+		Transponder: int32(p.Transponder),
+	}
+}
+
+// toATC converts a controller
+func toATC(a dataserver.ATC) *pb.ATC {
+	return &pb.ATC{
+		Server:      a.Server,
+		Callsign:    a.Callsign,
+		Type:        string(a.ClientType),
+		Member:      toMember(a.Member),
+		Rating:      int32(a.Rating),
+		Frequency:   int32(a.Frequency),
+		Facility:    int32(a.FacilityType),
+		Range:       int32(a.VisualRange),
+		Latitude:    a.Latitude,
+		Longitude:   a.Longitude,
+		Atis:        a.ATIS,
+		LogonTime:   toTimestamp(a.LogonTime),
+		LastUpdated: toTimestamp(a.LastUpdated),
+	}
+}
+
+// toSession converts a completed session
+func toSession(s dataserver.Session) *pb.Session {
+	session := &pb.Session{
+		Server:     s.Server,
+		Callsign:   s.Callsign,
+		Type:       string(s.ClientType),
+		Member:     toMember(s.Member),
+		Rating:     int32(s.Rating),
+		Latitude:   s.Latitude,
+		Longitude:  s.Longitude,
+		Altitude:   int32(s.Altitude),
+		LogonTime:  toTimestamp(s.LogonTime),
+		LogoffTime: toTimestamp(s.LogoffTime),
+		Duration:   int64(s.Duration),
+		Reason:     s.Reason,
+	}
+	if s.FlightPlan != nil {
+		session.Plan = toFlightPlan(*s.FlightPlan)
+	}
+	return session
+}
+
+// toAnomaly converts a conflicting client report
+func toAnomaly(a dataserver.Anomaly) *pb.Anomaly {
+	return &pb.Anomaly{
+		Cid:       int32(a.CID),
+		Callsign:  a.Callsign,
+		Callsigns: a.Callsigns,
+		Servers:   a.Servers,
+	}
+}
+
+// toClientList converts a client list
+func toClientList(c dataserver.ClientList) *pb.ClientList {
+	clientList := &pb.ClientList{
+		Pilots:      make([]*pb.Pilot, 0, len(c.PilotData)),
+		Controllers: make([]*pb.ATC, 0, len(c.ATCData)),
+		Serial:      c.Serial,
+	}
+	for _, v := range c.PilotData {
+		clientList.Pilots = append(clientList.Pilots, toPilot(v))
+	}
+	for _, v := range c.ATCData {
+		clientList.Controllers = append(clientList.Controllers, toATC(v))
+	}
+	return clientList
+}
+
+// toEvent converts a change event, setting whichever data it carries
+func toEvent(e dataserver.Event) *pb.Event {
+	event := &pb.Event{
+		Id:          e.ID,
+		MessageType: e.MessageType,
+		Callsign:    e.Callsign,
+		Timestamp:   toTimestamp(e.Timestamp),
+	}
+	switch v := e.Data.(type) {
+	case dataserver.Pilot:
+		event.Data = &pb.Event_Pilot{Pilot: toPilot(v)}
+	case dataserver.ATC:
+		event.Data = &pb.Event_Controller{Controller: toATC(v)}
+	case dataserver.FlightPlan:
+		event.Data = &pb.Event_Plan{Plan: toFlightPlan(v)}
+	case dataserver.Session:
+		event.Data = &pb.Event_Session{Session: toSession(v)}
+	case dataserver.Anomaly:
+		event.Data = &pb.Event_Anomaly{Anomaly: toAnomaly(v)}
+	}
+	return event
+}
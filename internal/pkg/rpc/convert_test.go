@@ -0,0 +1,83 @@
+package rpc
+
+import (
+	"dataserver/internal/pkg/dataserver"
+	"dataserver/internal/pkg/rpc/pb"
+	"github.com/golang/protobuf/proto"
+	"testing"
+	"time"
+)
+
+func TestToTimestamp(t *testing.T) {
+	if got := toTimestamp(time.Time{}); got != nil {
+		t.Errorf("toTimestamp() of an unset time = %v, want nil", got)
+	}
+	now := time.Date(2020, 1, 1, 12, 30, 0, 5, time.UTC)
+	if got := toTimestamp(now); got.Seconds != now.Unix() || got.Nanos != 5 {
+		t.Errorf("toTimestamp() = %v, want %v", got, now)
+	}
+}
+
+func TestToEvent(t *testing.T) {
+	now := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	plan := dataserver.FlightPlan{Departure: "EGLL", Arrival: "KJFK", Aircraft: "B77W"}
+	tests := []struct {
+		name string
+		data interface{}
+		want pb.Event
+	}{
+		{"Pilot", dataserver.Pilot{Callsign: "BAW2", Member: dataserver.MemberData{CID: 2, Name: "Two"}, Altitude: 35000, FlightPlan: plan, LastUpdated: now},
+			pb.Event{Data: &pb.Event_Pilot{Pilot: &pb.Pilot{
+				Callsign:    "BAW2",
+				Member:      &pb.MemberData{Cid: 2, Name: "Two"},
+				Altitude:    35000,
+				Plan:        &pb.FlightPlan{Departure: "EGLL", Arrival: "KJFK", Aircraft: "B77W", Time: &pb.FlightPlanTime{}},
+				LastUpdated: toTimestamp(now),
+			}}}},
+		{"Controller", dataserver.ATC{Callsign: "EGLL_TWR", ClientType: dataserver.ClientTypeController, Frequency: 18300, FacilityType: 4, ATIS: "Information A"},
+			pb.Event{Data: &pb.Event_Controller{Controller: &pb.ATC{
+				Callsign:  "EGLL_TWR",
+				Type:      "controller",
+				Member:    &pb.MemberData{},
+				Frequency: 18300,
+				Facility:  4,
+				Atis:      "Information A",
+			}}}},
+		{"Flight plan", plan, pb.Event{Data: &pb.Event_Plan{Plan: &pb.FlightPlan{Departure: "EGLL", Arrival: "KJFK", Aircraft: "B77W", Time: &pb.FlightPlanTime{}}}}},
+		{"Session without plan", dataserver.Session{Callsign: "EGLL_TWR", ClientType: dataserver.ClientTypeController, Duration: 60, Reason: dataserver.SessionEndRemoved},
+			pb.Event{Data: &pb.Event_Session{Session: &pb.Session{Callsign: "EGLL_TWR", Type: "controller", Member: &pb.MemberData{}, Duration: 60, Reason: "removed"}}}},
+		{"Session with plan", dataserver.Session{Callsign: "BAW2", ClientType: dataserver.ClientTypePilot, FlightPlan: &plan},
+			pb.Event{Data: &pb.Event_Session{Session: &pb.Session{Callsign: "BAW2", Type: "pilot", Member: &pb.MemberData{},
+				Plan: &pb.FlightPlan{Departure: "EGLL", Arrival: "KJFK", Aircraft: "B77W", Time: &pb.FlightPlanTime{}}}}}},
+		{"Anomaly", dataserver.Anomaly{CID: 2, Callsigns: []string{"BAW2", "BAW3"}, Servers: []string{"UK", "EU"}},
+			pb.Event{Data: &pb.Event_Anomaly{Anomaly: &pb.Anomaly{Cid: 2, Callsigns: []string{"BAW2", "BAW3"}, Servers: []string{"UK", "EU"}}}}},
+		{"Unknown", "BAW2", pb.Event{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want.Id = 7
+			tt.want.MessageType = "update"
+			tt.want.Callsign = "BAW2"
+			tt.want.Timestamp = toTimestamp(now)
+			got := toEvent(dataserver.Event{ID: 7, MessageType: "update", Callsign: "BAW2", Timestamp: now, Data: tt.data})
+			if !proto.Equal(got, &tt.want) {
+				t.Errorf("toEvent() = %v, want %v", got, &tt.want)
+			}
+		})
+	}
+}
+
+func TestToClientList(t *testing.T) {
+	got := toClientList(dataserver.ClientList{
+		Serial:    3,
+		PilotData: []dataserver.Pilot{{Callsign: "BAW2"}},
+		ATCData:   []dataserver.ATC{{Callsign: "EGLL_TWR"}, {Callsign: "EGLL_GND"}},
+	})
+	if got.Serial != 3 || len(got.Pilots) != 1 || len(got.Controllers) != 2 || got.Controllers[1].Callsign != "EGLL_GND" {
+		t.Errorf("toClientList() = %v", got)
+	}
+	empty := toClientList(dataserver.ClientList{})
+	if empty.Pilots == nil || empty.Controllers == nil {
+		t.Errorf("toClientList() of an empty list = %v, want empty lists", empty)
+	}
+}
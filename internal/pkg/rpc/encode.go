@@ -0,0 +1,19 @@
+package rpc
+
+import (
+	"dataserver/internal/pkg/dataserver"
+	"github.com/golang/protobuf/proto"
+	"github.com/pkg/errors"
+)
+
+// ProtobufFileName is the data file encoded as a dataserver.v1.ClientList message.
+const ProtobufFileName = "vatsim-data.pb"
+
+// EncodeProtobuf encodes the client list as a ClientList message from api/dataserver.proto.
+func EncodeProtobuf(clientList dataserver.ClientList) ([]byte, error) {
+	data, err := proto.Marshal(toClientList(clientList))
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return data, nil
+}
@@ -0,0 +1,106 @@
+package rpc
+
+import (
+	"dataserver/internal/pkg/dataserver"
+	"dataserver/internal/pkg/rpc/pb"
+	"fmt"
+	"github.com/golang/protobuf/proto"
+	"testing"
+	"time"
+)
+
+// benchmarkClientList is about the size of a busy evening on the network.
+func benchmarkClientList() dataserver.ClientList {
+	now := time.Now().UTC()
+	clientList := dataserver.ClientList{Serial: 1}
+	for i := 0; i < 1500; i++ {
+		clientList.PilotData = append(clientList.PilotData, dataserver.Pilot{
+			Server:    "USA-EAST",
+			Callsign:  fmt.Sprintf("AAL%d", i),
+			Member:    dataserver.MemberData{CID: 1000000 + i, Name: "Pilot Name"},
+			Rating:    1,
+			Latitude:  40.6398 + float64(i)/100,
+			Longitude: -73.7789 + float64(i)/100,
+			Altitude:  35000,
+			Speed:     450,
+			Heading:   270,
+			FlightPlan: dataserver.FlightPlan{
+				FlightRules: "I",
+				Aircraft:    "H/B77W/L",
+				CruiseSpeed: "490",
+				Departure:   "KJFK",
+				Arrival:     "EGLL",
+				Altitude:    "35000",
+				Route:       "HAPIE3 HAPIE DCT YAHOO DCT DOVEY 4250N 4840N 5230N 5520N 5510W BURAK DCT",
+				Remarks:     "PBN/A1B1C1D1L1O1S2 DOF/191102 REG/N123AA /V/",
+			},
+			LogonTime:   now,
+			LastUpdated: now,
+			Transponder: 2200,
+		})
+	}
+	for i := 0; i < 300; i++ {
+		clientList.ATCData = append(clientList.ATCData, dataserver.ATC{
+			Server:       "USA-EAST",
+			Callsign:     fmt.Sprintf("KJFK_%d_TWR", i),
+			ClientType:   dataserver.ClientTypeController,
+			Member:       dataserver.MemberData{CID: 1100000 + i, Name: "Controller Name"},
+			Rating:       5,
+			Frequency:    19100,
+			FacilityType: 4,
+			VisualRange:  50,
+			Latitude:     40.6398,
+			Longitude:    -73.7789,
+			ATIS:         "Kennedy Tower\nATIS information Alpha",
+			LogonTime:    now,
+			LastUpdated:  now,
+		})
+	}
+	return clientList
+}
+
+func TestEncodeProtobuf(t *testing.T) {
+	clientList := benchmarkClientList()
+	data, err := EncodeProtobuf(clientList)
+	if err != nil {
+		t.Fatalf("EncodeProtobuf() error = %v", err)
+	}
+	decoded := &pb.ClientList{}
+	err = proto.Unmarshal(data, decoded)
+	if err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if len(decoded.Pilots) != 1500 || len(decoded.Controllers) != 300 || decoded.Serial != 1 {
+		t.Errorf("EncodeProtobuf() decoded %d pilots, %d controllers and serial %d", len(decoded.Pilots), len(decoded.Controllers), decoded.Serial)
+	}
+	if decoded.Pilots[0].Callsign != "AAL0" || decoded.Pilots[0].Transponder != 2200 {
+		t.Errorf("EncodeProtobuf() decoded pilot %v", decoded.Pilots[0])
+	}
+}
+
+func benchmarkEncoder(b *testing.B, encode func(dataserver.ClientList) ([]byte, error)) {
+	clientList := benchmarkClientList()
+	var size int
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		data, err := encode(clientList)
+		if err != nil {
+			b.Fatal(err)
+		}
+		size = len(data)
+	}
+	b.Logf("encoded %d bytes", size)
+}
+
+func BenchmarkEncodeJSON(b *testing.B) {
+	benchmarkEncoder(b, dataserver.EncodeJSON)
+}
+
+func BenchmarkEncodeProtobuf(b *testing.B) {
+	benchmarkEncoder(b, EncodeProtobuf)
+}
+
+func BenchmarkEncodeMessagePack(b *testing.B) {
+	benchmarkEncoder(b, dataserver.EncodeMessagePack)
+}
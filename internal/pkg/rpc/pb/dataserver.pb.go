@@ -0,0 +1,1638 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// source: dataserver.proto
+
+package pb
+
+import (
+	context "context"
+	fmt "fmt"
+	proto "github.com/golang/protobuf/proto"
+	timestamp "github.com/golang/protobuf/ptypes/timestamp"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	math "math"
+)
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ = proto.Marshal
+var _ = fmt.Errorf
+var _ = math.Inf
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the proto package it is being compiled against.
+// A compilation error at this line likely means your copy of the
+// proto package needs to be updated.
+const _ = proto.ProtoPackageIsVersion3 // please upgrade the proto package
+
+type MemberData struct {
+	Cid                  int32    `protobuf:"varint,1,opt,name=cid,proto3" json:"cid,omitempty"`
+	Name                 string   `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *MemberData) Reset()         { *m = MemberData{} }
+func (m *MemberData) String() string { return proto.CompactTextString(m) }
+func (*MemberData) ProtoMessage()    {}
+func (*MemberData) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{0}
+}
+
+func (m *MemberData) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_MemberData.Unmarshal(m, b)
+}
+func (m *MemberData) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_MemberData.Marshal(b, m, deterministic)
+}
+func (m *MemberData) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_MemberData.Merge(m, src)
+}
+func (m *MemberData) XXX_Size() int {
+	return xxx_messageInfo_MemberData.Size(m)
+}
+func (m *MemberData) XXX_DiscardUnknown() {
+	xxx_messageInfo_MemberData.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_MemberData proto.InternalMessageInfo
+
+func (m *MemberData) GetCid() int32 {
+	if m != nil {
+		return m.Cid
+	}
+	return 0
+}
+
+func (m *MemberData) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type FlightPlanTime struct {
+	Departure            string   `protobuf:"bytes,1,opt,name=departure,proto3" json:"departure,omitempty"`
+	HoursEnroute         string   `protobuf:"bytes,2,opt,name=hours_enroute,json=hoursEnroute,proto3" json:"hours_enroute,omitempty"`
+	MinutesEnroute       string   `protobuf:"bytes,3,opt,name=minutes_enroute,json=minutesEnroute,proto3" json:"minutes_enroute,omitempty"`
+	HoursFuel            string   `protobuf:"bytes,4,opt,name=hours_fuel,json=hoursFuel,proto3" json:"hours_fuel,omitempty"`
+	MinutesFuel          string   `protobuf:"bytes,5,opt,name=minutes_fuel,json=minutesFuel,proto3" json:"minutes_fuel,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *FlightPlanTime) Reset()         { *m = FlightPlanTime{} }
+func (m *FlightPlanTime) String() string { return proto.CompactTextString(m) }
+func (*FlightPlanTime) ProtoMessage()    {}
+func (*FlightPlanTime) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{1}
+}
+
+func (m *FlightPlanTime) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FlightPlanTime.Unmarshal(m, b)
+}
+func (m *FlightPlanTime) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FlightPlanTime.Marshal(b, m, deterministic)
+}
+func (m *FlightPlanTime) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FlightPlanTime.Merge(m, src)
+}
+func (m *FlightPlanTime) XXX_Size() int {
+	return xxx_messageInfo_FlightPlanTime.Size(m)
+}
+func (m *FlightPlanTime) XXX_DiscardUnknown() {
+	xxx_messageInfo_FlightPlanTime.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FlightPlanTime proto.InternalMessageInfo
+
+func (m *FlightPlanTime) GetDeparture() string {
+	if m != nil {
+		return m.Departure
+	}
+	return ""
+}
+
+func (m *FlightPlanTime) GetHoursEnroute() string {
+	if m != nil {
+		return m.HoursEnroute
+	}
+	return ""
+}
+
+func (m *FlightPlanTime) GetMinutesEnroute() string {
+	if m != nil {
+		return m.MinutesEnroute
+	}
+	return ""
+}
+
+func (m *FlightPlanTime) GetHoursFuel() string {
+	if m != nil {
+		return m.HoursFuel
+	}
+	return ""
+}
+
+func (m *FlightPlanTime) GetMinutesFuel() string {
+	if m != nil {
+		return m.MinutesFuel
+	}
+	return ""
+}
+
+type FlightPlan struct {
+	FlightRules          string          `protobuf:"bytes,1,opt,name=flight_rules,json=flightRules,proto3" json:"flight_rules,omitempty"`
+	Aircraft             string          `protobuf:"bytes,2,opt,name=aircraft,proto3" json:"aircraft,omitempty"`
+	CruiseSpeed          string          `protobuf:"bytes,3,opt,name=cruise_speed,json=cruiseSpeed,proto3" json:"cruise_speed,omitempty"`
+	Departure            string          `protobuf:"bytes,4,opt,name=departure,proto3" json:"departure,omitempty"`
+	Arrival              string          `protobuf:"bytes,5,opt,name=arrival,proto3" json:"arrival,omitempty"`
+	Altitude             string          `protobuf:"bytes,6,opt,name=altitude,proto3" json:"altitude,omitempty"`
+	Alternate            string          `protobuf:"bytes,7,opt,name=alternate,proto3" json:"alternate,omitempty"`
+	Route                string          `protobuf:"bytes,8,opt,name=route,proto3" json:"route,omitempty"`
+	Time                 *FlightPlanTime `protobuf:"bytes,9,opt,name=time,proto3" json:"time,omitempty"`
+	Remarks              string          `protobuf:"bytes,10,opt,name=remarks,proto3" json:"remarks,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *FlightPlan) Reset()         { *m = FlightPlan{} }
+func (m *FlightPlan) String() string { return proto.CompactTextString(m) }
+func (*FlightPlan) ProtoMessage()    {}
+func (*FlightPlan) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{2}
+}
+
+func (m *FlightPlan) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_FlightPlan.Unmarshal(m, b)
+}
+func (m *FlightPlan) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_FlightPlan.Marshal(b, m, deterministic)
+}
+func (m *FlightPlan) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_FlightPlan.Merge(m, src)
+}
+func (m *FlightPlan) XXX_Size() int {
+	return xxx_messageInfo_FlightPlan.Size(m)
+}
+func (m *FlightPlan) XXX_DiscardUnknown() {
+	xxx_messageInfo_FlightPlan.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_FlightPlan proto.InternalMessageInfo
+
+func (m *FlightPlan) GetFlightRules() string {
+	if m != nil {
+		return m.FlightRules
+	}
+	return ""
+}
+
+func (m *FlightPlan) GetAircraft() string {
+	if m != nil {
+		return m.Aircraft
+	}
+	return ""
+}
+
+func (m *FlightPlan) GetCruiseSpeed() string {
+	if m != nil {
+		return m.CruiseSpeed
+	}
+	return ""
+}
+
+func (m *FlightPlan) GetDeparture() string {
+	if m != nil {
+		return m.Departure
+	}
+	return ""
+}
+
+func (m *FlightPlan) GetArrival() string {
+	if m != nil {
+		return m.Arrival
+	}
+	return ""
+}
+
+func (m *FlightPlan) GetAltitude() string {
+	if m != nil {
+		return m.Altitude
+	}
+	return ""
+}
+
+func (m *FlightPlan) GetAlternate() string {
+	if m != nil {
+		return m.Alternate
+	}
+	return ""
+}
+
+func (m *FlightPlan) GetRoute() string {
+	if m != nil {
+		return m.Route
+	}
+	return ""
+}
+
+func (m *FlightPlan) GetTime() *FlightPlanTime {
+	if m != nil {
+		return m.Time
+	}
+	return nil
+}
+
+func (m *FlightPlan) GetRemarks() string {
+	if m != nil {
+		return m.Remarks
+	}
+	return ""
+}
+
+type Pilot struct {
+	Server               string               `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Callsign             string               `protobuf:"bytes,2,opt,name=callsign,proto3" json:"callsign,omitempty"`
+	Member               *MemberData          `protobuf:"bytes,3,opt,name=member,proto3" json:"member,omitempty"`
+	Rating               int32                `protobuf:"varint,4,opt,name=rating,proto3" json:"rating,omitempty"`
+	Latitude             float64              `protobuf:"fixed64,5,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude            float64              `protobuf:"fixed64,6,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Altitude             int32                `protobuf:"varint,7,opt,name=altitude,proto3" json:"altitude,omitempty"`
+	Speed                int32                `protobuf:"varint,8,opt,name=speed,proto3" json:"speed,omitempty"`
+	Heading              int32                `protobuf:"varint,9,opt,name=heading,proto3" json:"heading,omitempty"`
+	Plan                 *FlightPlan          `protobuf:"bytes,10,opt,name=plan,proto3" json:"plan,omitempty"`
+	LogonTime            *timestamp.Timestamp `protobuf:"bytes,11,opt,name=logon_time,json=logonTime,proto3" json:"logon_time,omitempty"`
+	LastUpdated          *timestamp.Timestamp `protobuf:"bytes,12,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	Transponder          int32                `protobuf:"varint,13,opt,name=transponder,proto3" json:"transponder,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Pilot) Reset()         { *m = Pilot{} }
+func (m *Pilot) String() string { return proto.CompactTextString(m) }
+func (*Pilot) ProtoMessage()    {}
+func (*Pilot) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{3}
+}
+
+func (m *Pilot) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Pilot.Unmarshal(m, b)
+}
+func (m *Pilot) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Pilot.Marshal(b, m, deterministic)
+}
+func (m *Pilot) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Pilot.Merge(m, src)
+}
+func (m *Pilot) XXX_Size() int {
+	return xxx_messageInfo_Pilot.Size(m)
+}
+func (m *Pilot) XXX_DiscardUnknown() {
+	xxx_messageInfo_Pilot.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Pilot proto.InternalMessageInfo
+
+func (m *Pilot) GetServer() string {
+	if m != nil {
+		return m.Server
+	}
+	return ""
+}
+
+func (m *Pilot) GetCallsign() string {
+	if m != nil {
+		return m.Callsign
+	}
+	return ""
+}
+
+func (m *Pilot) GetMember() *MemberData {
+	if m != nil {
+		return m.Member
+	}
+	return nil
+}
+
+func (m *Pilot) GetRating() int32 {
+	if m != nil {
+		return m.Rating
+	}
+	return 0
+}
+
+func (m *Pilot) GetLatitude() float64 {
+	if m != nil {
+		return m.Latitude
+	}
+	return 0
+}
+
+func (m *Pilot) GetLongitude() float64 {
+	if m != nil {
+		return m.Longitude
+	}
+	return 0
+}
+
+func (m *Pilot) GetAltitude() int32 {
+	if m != nil {
+		return m.Altitude
+	}
+	return 0
+}
+
+func (m *Pilot) GetSpeed() int32 {
+	if m != nil {
+		return m.Speed
+	}
+	return 0
+}
+
+func (m *Pilot) GetHeading() int32 {
+	if m != nil {
+		return m.Heading
+	}
+	return 0
+}
+
+func (m *Pilot) GetPlan() *FlightPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
+}
+
+func (m *Pilot) GetLogonTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.LogonTime
+	}
+	return nil
+}
+
+func (m *Pilot) GetLastUpdated() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastUpdated
+	}
+	return nil
+}
+
+func (m *Pilot) GetTransponder() int32 {
+	if m != nil {
+		return m.Transponder
+	}
+	return 0
+}
+
+type ATC struct {
+	Server               string               `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Callsign             string               `protobuf:"bytes,2,opt,name=callsign,proto3" json:"callsign,omitempty"`
+	Type                 string               `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Member               *MemberData          `protobuf:"bytes,4,opt,name=member,proto3" json:"member,omitempty"`
+	Rating               int32                `protobuf:"varint,5,opt,name=rating,proto3" json:"rating,omitempty"`
+	Frequency            int32                `protobuf:"varint,6,opt,name=frequency,proto3" json:"frequency,omitempty"`
+	Facility             int32                `protobuf:"varint,7,opt,name=facility,proto3" json:"facility,omitempty"`
+	Range                int32                `protobuf:"varint,8,opt,name=range,proto3" json:"range,omitempty"`
+	Latitude             float64              `protobuf:"fixed64,9,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude            float64              `protobuf:"fixed64,10,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Atis                 string               `protobuf:"bytes,11,opt,name=atis,proto3" json:"atis,omitempty"`
+	LogonTime            *timestamp.Timestamp `protobuf:"bytes,12,opt,name=logon_time,json=logonTime,proto3" json:"logon_time,omitempty"`
+	LastUpdated          *timestamp.Timestamp `protobuf:"bytes,13,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *ATC) Reset()         { *m = ATC{} }
+func (m *ATC) String() string { return proto.CompactTextString(m) }
+func (*ATC) ProtoMessage()    {}
+func (*ATC) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{4}
+}
+
+func (m *ATC) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ATC.Unmarshal(m, b)
+}
+func (m *ATC) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ATC.Marshal(b, m, deterministic)
+}
+func (m *ATC) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ATC.Merge(m, src)
+}
+func (m *ATC) XXX_Size() int {
+	return xxx_messageInfo_ATC.Size(m)
+}
+func (m *ATC) XXX_DiscardUnknown() {
+	xxx_messageInfo_ATC.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ATC proto.InternalMessageInfo
+
+func (m *ATC) GetServer() string {
+	if m != nil {
+		return m.Server
+	}
+	return ""
+}
+
+func (m *ATC) GetCallsign() string {
+	if m != nil {
+		return m.Callsign
+	}
+	return ""
+}
+
+func (m *ATC) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *ATC) GetMember() *MemberData {
+	if m != nil {
+		return m.Member
+	}
+	return nil
+}
+
+func (m *ATC) GetRating() int32 {
+	if m != nil {
+		return m.Rating
+	}
+	return 0
+}
+
+func (m *ATC) GetFrequency() int32 {
+	if m != nil {
+		return m.Frequency
+	}
+	return 0
+}
+
+func (m *ATC) GetFacility() int32 {
+	if m != nil {
+		return m.Facility
+	}
+	return 0
+}
+
+func (m *ATC) GetRange() int32 {
+	if m != nil {
+		return m.Range
+	}
+	return 0
+}
+
+func (m *ATC) GetLatitude() float64 {
+	if m != nil {
+		return m.Latitude
+	}
+	return 0
+}
+
+func (m *ATC) GetLongitude() float64 {
+	if m != nil {
+		return m.Longitude
+	}
+	return 0
+}
+
+func (m *ATC) GetAtis() string {
+	if m != nil {
+		return m.Atis
+	}
+	return ""
+}
+
+func (m *ATC) GetLogonTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.LogonTime
+	}
+	return nil
+}
+
+func (m *ATC) GetLastUpdated() *timestamp.Timestamp {
+	if m != nil {
+		return m.LastUpdated
+	}
+	return nil
+}
+
+type Session struct {
+	Server               string               `protobuf:"bytes,1,opt,name=server,proto3" json:"server,omitempty"`
+	Callsign             string               `protobuf:"bytes,2,opt,name=callsign,proto3" json:"callsign,omitempty"`
+	Type                 string               `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Member               *MemberData          `protobuf:"bytes,4,opt,name=member,proto3" json:"member,omitempty"`
+	Rating               int32                `protobuf:"varint,5,opt,name=rating,proto3" json:"rating,omitempty"`
+	Latitude             float64              `protobuf:"fixed64,6,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude            float64              `protobuf:"fixed64,7,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	Altitude             int32                `protobuf:"varint,8,opt,name=altitude,proto3" json:"altitude,omitempty"`
+	Plan                 *FlightPlan          `protobuf:"bytes,9,opt,name=plan,proto3" json:"plan,omitempty"`
+	LogonTime            *timestamp.Timestamp `protobuf:"bytes,10,opt,name=logon_time,json=logonTime,proto3" json:"logon_time,omitempty"`
+	LogoffTime           *timestamp.Timestamp `protobuf:"bytes,11,opt,name=logoff_time,json=logoffTime,proto3" json:"logoff_time,omitempty"`
+	Duration             int64                `protobuf:"varint,12,opt,name=duration,proto3" json:"duration,omitempty"`
+	Reason               string               `protobuf:"bytes,13,opt,name=reason,proto3" json:"reason,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}             `json:"-"`
+	XXX_unrecognized     []byte               `json:"-"`
+	XXX_sizecache        int32                `json:"-"`
+}
+
+func (m *Session) Reset()         { *m = Session{} }
+func (m *Session) String() string { return proto.CompactTextString(m) }
+func (*Session) ProtoMessage()    {}
+func (*Session) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{5}
+}
+
+func (m *Session) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Session.Unmarshal(m, b)
+}
+func (m *Session) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Session.Marshal(b, m, deterministic)
+}
+func (m *Session) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Session.Merge(m, src)
+}
+func (m *Session) XXX_Size() int {
+	return xxx_messageInfo_Session.Size(m)
+}
+func (m *Session) XXX_DiscardUnknown() {
+	xxx_messageInfo_Session.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Session proto.InternalMessageInfo
+
+func (m *Session) GetServer() string {
+	if m != nil {
+		return m.Server
+	}
+	return ""
+}
+
+func (m *Session) GetCallsign() string {
+	if m != nil {
+		return m.Callsign
+	}
+	return ""
+}
+
+func (m *Session) GetType() string {
+	if m != nil {
+		return m.Type
+	}
+	return ""
+}
+
+func (m *Session) GetMember() *MemberData {
+	if m != nil {
+		return m.Member
+	}
+	return nil
+}
+
+func (m *Session) GetRating() int32 {
+	if m != nil {
+		return m.Rating
+	}
+	return 0
+}
+
+func (m *Session) GetLatitude() float64 {
+	if m != nil {
+		return m.Latitude
+	}
+	return 0
+}
+
+func (m *Session) GetLongitude() float64 {
+	if m != nil {
+		return m.Longitude
+	}
+	return 0
+}
+
+func (m *Session) GetAltitude() int32 {
+	if m != nil {
+		return m.Altitude
+	}
+	return 0
+}
+
+func (m *Session) GetPlan() *FlightPlan {
+	if m != nil {
+		return m.Plan
+	}
+	return nil
+}
+
+func (m *Session) GetLogonTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.LogonTime
+	}
+	return nil
+}
+
+func (m *Session) GetLogoffTime() *timestamp.Timestamp {
+	if m != nil {
+		return m.LogoffTime
+	}
+	return nil
+}
+
+func (m *Session) GetDuration() int64 {
+	if m != nil {
+		return m.Duration
+	}
+	return 0
+}
+
+func (m *Session) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+type Anomaly struct {
+	Cid                  int32    `protobuf:"varint,1,opt,name=cid,proto3" json:"cid,omitempty"`
+	Callsign             string   `protobuf:"bytes,2,opt,name=callsign,proto3" json:"callsign,omitempty"`
+	Callsigns            []string `protobuf:"bytes,3,rep,name=callsigns,proto3" json:"callsigns,omitempty"`
+	Servers              []string `protobuf:"bytes,4,rep,name=servers,proto3" json:"servers,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Anomaly) Reset()         { *m = Anomaly{} }
+func (m *Anomaly) String() string { return proto.CompactTextString(m) }
+func (*Anomaly) ProtoMessage()    {}
+func (*Anomaly) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{6}
+}
+
+func (m *Anomaly) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Anomaly.Unmarshal(m, b)
+}
+func (m *Anomaly) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Anomaly.Marshal(b, m, deterministic)
+}
+func (m *Anomaly) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Anomaly.Merge(m, src)
+}
+func (m *Anomaly) XXX_Size() int {
+	return xxx_messageInfo_Anomaly.Size(m)
+}
+func (m *Anomaly) XXX_DiscardUnknown() {
+	xxx_messageInfo_Anomaly.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Anomaly proto.InternalMessageInfo
+
+func (m *Anomaly) GetCid() int32 {
+	if m != nil {
+		return m.Cid
+	}
+	return 0
+}
+
+func (m *Anomaly) GetCallsign() string {
+	if m != nil {
+		return m.Callsign
+	}
+	return ""
+}
+
+func (m *Anomaly) GetCallsigns() []string {
+	if m != nil {
+		return m.Callsigns
+	}
+	return nil
+}
+
+func (m *Anomaly) GetServers() []string {
+	if m != nil {
+		return m.Servers
+	}
+	return nil
+}
+
+type Client struct {
+	// Types that are valid to be assigned to Client:
+	//	*Client_Pilot
+	//	*Client_Controller
+	Client               isClient_Client `protobuf_oneof:"client"`
+	XXX_NoUnkeyedLiteral struct{}        `json:"-"`
+	XXX_unrecognized     []byte          `json:"-"`
+	XXX_sizecache        int32           `json:"-"`
+}
+
+func (m *Client) Reset()         { *m = Client{} }
+func (m *Client) String() string { return proto.CompactTextString(m) }
+func (*Client) ProtoMessage()    {}
+func (*Client) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{7}
+}
+
+func (m *Client) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Client.Unmarshal(m, b)
+}
+func (m *Client) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Client.Marshal(b, m, deterministic)
+}
+func (m *Client) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Client.Merge(m, src)
+}
+func (m *Client) XXX_Size() int {
+	return xxx_messageInfo_Client.Size(m)
+}
+func (m *Client) XXX_DiscardUnknown() {
+	xxx_messageInfo_Client.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Client proto.InternalMessageInfo
+
+type isClient_Client interface {
+	isClient_Client()
+}
+
+type Client_Pilot struct {
+	Pilot *Pilot `protobuf:"bytes,1,opt,name=pilot,proto3,oneof"`
+}
+
+type Client_Controller struct {
+	Controller *ATC `protobuf:"bytes,2,opt,name=controller,proto3,oneof"`
+}
+
+func (*Client_Pilot) isClient_Client() {}
+
+func (*Client_Controller) isClient_Client() {}
+
+func (m *Client) GetClient() isClient_Client {
+	if m != nil {
+		return m.Client
+	}
+	return nil
+}
+
+func (m *Client) GetPilot() *Pilot {
+	if x, ok := m.GetClient().(*Client_Pilot); ok {
+		return x.Pilot
+	}
+	return nil
+}
+
+func (m *Client) GetController() *ATC {
+	if x, ok := m.GetClient().(*Client_Controller); ok {
+		return x.Controller
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Client) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Client_Pilot)(nil),
+		(*Client_Controller)(nil),
+	}
+}
+
+// ClientList is also the schema of the vatsim-data.pb data file.
+type ClientList struct {
+	Pilots               []*Pilot `protobuf:"bytes,1,rep,name=pilots,proto3" json:"pilots,omitempty"`
+	Controllers          []*ATC   `protobuf:"bytes,2,rep,name=controllers,proto3" json:"controllers,omitempty"`
+	Serial               uint64   `protobuf:"varint,3,opt,name=serial,proto3" json:"serial,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ClientList) Reset()         { *m = ClientList{} }
+func (m *ClientList) String() string { return proto.CompactTextString(m) }
+func (*ClientList) ProtoMessage()    {}
+func (*ClientList) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{8}
+}
+
+func (m *ClientList) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ClientList.Unmarshal(m, b)
+}
+func (m *ClientList) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ClientList.Marshal(b, m, deterministic)
+}
+func (m *ClientList) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ClientList.Merge(m, src)
+}
+func (m *ClientList) XXX_Size() int {
+	return xxx_messageInfo_ClientList.Size(m)
+}
+func (m *ClientList) XXX_DiscardUnknown() {
+	xxx_messageInfo_ClientList.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ClientList proto.InternalMessageInfo
+
+func (m *ClientList) GetPilots() []*Pilot {
+	if m != nil {
+		return m.Pilots
+	}
+	return nil
+}
+
+func (m *ClientList) GetControllers() []*ATC {
+	if m != nil {
+		return m.Controllers
+	}
+	return nil
+}
+
+func (m *ClientList) GetSerial() uint64 {
+	if m != nil {
+		return m.Serial
+	}
+	return 0
+}
+
+// Event is a change to the client list. Snapshot events have an id of zero.
+type Event struct {
+	Id          uint64               `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	MessageType string               `protobuf:"bytes,2,opt,name=message_type,json=messageType,proto3" json:"message_type,omitempty"`
+	Callsign    string               `protobuf:"bytes,3,opt,name=callsign,proto3" json:"callsign,omitempty"`
+	Timestamp   *timestamp.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	// Types that are valid to be assigned to Data:
+	//	*Event_Pilot
+	//	*Event_Controller
+	//	*Event_Plan
+	//	*Event_Session
+	//	*Event_Anomaly
+	Data                 isEvent_Data `protobuf_oneof:"data"`
+	XXX_NoUnkeyedLiteral struct{}     `json:"-"`
+	XXX_unrecognized     []byte       `json:"-"`
+	XXX_sizecache        int32        `json:"-"`
+}
+
+func (m *Event) Reset()         { *m = Event{} }
+func (m *Event) String() string { return proto.CompactTextString(m) }
+func (*Event) ProtoMessage()    {}
+func (*Event) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{9}
+}
+
+func (m *Event) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Event.Unmarshal(m, b)
+}
+func (m *Event) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Event.Marshal(b, m, deterministic)
+}
+func (m *Event) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Event.Merge(m, src)
+}
+func (m *Event) XXX_Size() int {
+	return xxx_messageInfo_Event.Size(m)
+}
+func (m *Event) XXX_DiscardUnknown() {
+	xxx_messageInfo_Event.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Event proto.InternalMessageInfo
+
+func (m *Event) GetId() uint64 {
+	if m != nil {
+		return m.Id
+	}
+	return 0
+}
+
+func (m *Event) GetMessageType() string {
+	if m != nil {
+		return m.MessageType
+	}
+	return ""
+}
+
+func (m *Event) GetCallsign() string {
+	if m != nil {
+		return m.Callsign
+	}
+	return ""
+}
+
+func (m *Event) GetTimestamp() *timestamp.Timestamp {
+	if m != nil {
+		return m.Timestamp
+	}
+	return nil
+}
+
+type isEvent_Data interface {
+	isEvent_Data()
+}
+
+type Event_Pilot struct {
+	Pilot *Pilot `protobuf:"bytes,5,opt,name=pilot,proto3,oneof"`
+}
+
+type Event_Controller struct {
+	Controller *ATC `protobuf:"bytes,6,opt,name=controller,proto3,oneof"`
+}
+
+type Event_Plan struct {
+	Plan *FlightPlan `protobuf:"bytes,7,opt,name=plan,proto3,oneof"`
+}
+
+type Event_Session struct {
+	Session *Session `protobuf:"bytes,8,opt,name=session,proto3,oneof"`
+}
+
+type Event_Anomaly struct {
+	Anomaly *Anomaly `protobuf:"bytes,9,opt,name=anomaly,proto3,oneof"`
+}
+
+func (*Event_Pilot) isEvent_Data() {}
+
+func (*Event_Controller) isEvent_Data() {}
+
+func (*Event_Plan) isEvent_Data() {}
+
+func (*Event_Session) isEvent_Data() {}
+
+func (*Event_Anomaly) isEvent_Data() {}
+
+func (m *Event) GetData() isEvent_Data {
+	if m != nil {
+		return m.Data
+	}
+	return nil
+}
+
+func (m *Event) GetPilot() *Pilot {
+	if x, ok := m.GetData().(*Event_Pilot); ok {
+		return x.Pilot
+	}
+	return nil
+}
+
+func (m *Event) GetController() *ATC {
+	if x, ok := m.GetData().(*Event_Controller); ok {
+		return x.Controller
+	}
+	return nil
+}
+
+func (m *Event) GetPlan() *FlightPlan {
+	if x, ok := m.GetData().(*Event_Plan); ok {
+		return x.Plan
+	}
+	return nil
+}
+
+func (m *Event) GetSession() *Session {
+	if x, ok := m.GetData().(*Event_Session); ok {
+		return x.Session
+	}
+	return nil
+}
+
+func (m *Event) GetAnomaly() *Anomaly {
+	if x, ok := m.GetData().(*Event_Anomaly); ok {
+		return x.Anomaly
+	}
+	return nil
+}
+
+// XXX_OneofWrappers is for the internal use of the proto package.
+func (*Event) XXX_OneofWrappers() []interface{} {
+	return []interface{}{
+		(*Event_Pilot)(nil),
+		(*Event_Controller)(nil),
+		(*Event_Plan)(nil),
+		(*Event_Session)(nil),
+		(*Event_Anomaly)(nil),
+	}
+}
+
+type BoundingBox struct {
+	South                float64  `protobuf:"fixed64,1,opt,name=south,proto3" json:"south,omitempty"`
+	West                 float64  `protobuf:"fixed64,2,opt,name=west,proto3" json:"west,omitempty"`
+	North                float64  `protobuf:"fixed64,3,opt,name=north,proto3" json:"north,omitempty"`
+	East                 float64  `protobuf:"fixed64,4,opt,name=east,proto3" json:"east,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *BoundingBox) Reset()         { *m = BoundingBox{} }
+func (m *BoundingBox) String() string { return proto.CompactTextString(m) }
+func (*BoundingBox) ProtoMessage()    {}
+func (*BoundingBox) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{10}
+}
+
+func (m *BoundingBox) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_BoundingBox.Unmarshal(m, b)
+}
+func (m *BoundingBox) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_BoundingBox.Marshal(b, m, deterministic)
+}
+func (m *BoundingBox) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_BoundingBox.Merge(m, src)
+}
+func (m *BoundingBox) XXX_Size() int {
+	return xxx_messageInfo_BoundingBox.Size(m)
+}
+func (m *BoundingBox) XXX_DiscardUnknown() {
+	xxx_messageInfo_BoundingBox.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_BoundingBox proto.InternalMessageInfo
+
+func (m *BoundingBox) GetSouth() float64 {
+	if m != nil {
+		return m.South
+	}
+	return 0
+}
+
+func (m *BoundingBox) GetWest() float64 {
+	if m != nil {
+		return m.West
+	}
+	return 0
+}
+
+func (m *BoundingBox) GetNorth() float64 {
+	if m != nil {
+		return m.North
+	}
+	return 0
+}
+
+func (m *BoundingBox) GetEast() float64 {
+	if m != nil {
+		return m.East
+	}
+	return 0
+}
+
+type Filter struct {
+	// callsign is a glob pattern such as BAW*.
+	Callsign string       `protobuf:"bytes,1,opt,name=callsign,proto3" json:"callsign,omitempty"`
+	Bbox     *BoundingBox `protobuf:"bytes,2,opt,name=bbox,proto3" json:"bbox,omitempty"`
+	// types limits the client types, such as pilot or controller.
+	Types                []string `protobuf:"bytes,3,rep,name=types,proto3" json:"types,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Filter) Reset()         { *m = Filter{} }
+func (m *Filter) String() string { return proto.CompactTextString(m) }
+func (*Filter) ProtoMessage()    {}
+func (*Filter) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{11}
+}
+
+func (m *Filter) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Filter.Unmarshal(m, b)
+}
+func (m *Filter) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Filter.Marshal(b, m, deterministic)
+}
+func (m *Filter) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Filter.Merge(m, src)
+}
+func (m *Filter) XXX_Size() int {
+	return xxx_messageInfo_Filter.Size(m)
+}
+func (m *Filter) XXX_DiscardUnknown() {
+	xxx_messageInfo_Filter.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Filter proto.InternalMessageInfo
+
+func (m *Filter) GetCallsign() string {
+	if m != nil {
+		return m.Callsign
+	}
+	return ""
+}
+
+func (m *Filter) GetBbox() *BoundingBox {
+	if m != nil {
+		return m.Bbox
+	}
+	return nil
+}
+
+func (m *Filter) GetTypes() []string {
+	if m != nil {
+		return m.Types
+	}
+	return nil
+}
+
+type ListClientsRequest struct {
+	Filter               *Filter  `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *ListClientsRequest) Reset()         { *m = ListClientsRequest{} }
+func (m *ListClientsRequest) String() string { return proto.CompactTextString(m) }
+func (*ListClientsRequest) ProtoMessage()    {}
+func (*ListClientsRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{12}
+}
+
+func (m *ListClientsRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_ListClientsRequest.Unmarshal(m, b)
+}
+func (m *ListClientsRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_ListClientsRequest.Marshal(b, m, deterministic)
+}
+func (m *ListClientsRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_ListClientsRequest.Merge(m, src)
+}
+func (m *ListClientsRequest) XXX_Size() int {
+	return xxx_messageInfo_ListClientsRequest.Size(m)
+}
+func (m *ListClientsRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_ListClientsRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_ListClientsRequest proto.InternalMessageInfo
+
+func (m *ListClientsRequest) GetFilter() *Filter {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+type GetClientRequest struct {
+	Callsign             string   `protobuf:"bytes,1,opt,name=callsign,proto3" json:"callsign,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetClientRequest) Reset()         { *m = GetClientRequest{} }
+func (m *GetClientRequest) String() string { return proto.CompactTextString(m) }
+func (*GetClientRequest) ProtoMessage()    {}
+func (*GetClientRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{13}
+}
+
+func (m *GetClientRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetClientRequest.Unmarshal(m, b)
+}
+func (m *GetClientRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetClientRequest.Marshal(b, m, deterministic)
+}
+func (m *GetClientRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetClientRequest.Merge(m, src)
+}
+func (m *GetClientRequest) XXX_Size() int {
+	return xxx_messageInfo_GetClientRequest.Size(m)
+}
+func (m *GetClientRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetClientRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetClientRequest proto.InternalMessageInfo
+
+func (m *GetClientRequest) GetCallsign() string {
+	if m != nil {
+		return m.Callsign
+	}
+	return ""
+}
+
+type GetMemberRequest struct {
+	Cid                  int32    `protobuf:"varint,1,opt,name=cid,proto3" json:"cid,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *GetMemberRequest) Reset()         { *m = GetMemberRequest{} }
+func (m *GetMemberRequest) String() string { return proto.CompactTextString(m) }
+func (*GetMemberRequest) ProtoMessage()    {}
+func (*GetMemberRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{14}
+}
+
+func (m *GetMemberRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_GetMemberRequest.Unmarshal(m, b)
+}
+func (m *GetMemberRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_GetMemberRequest.Marshal(b, m, deterministic)
+}
+func (m *GetMemberRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_GetMemberRequest.Merge(m, src)
+}
+func (m *GetMemberRequest) XXX_Size() int {
+	return xxx_messageInfo_GetMemberRequest.Size(m)
+}
+func (m *GetMemberRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_GetMemberRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_GetMemberRequest proto.InternalMessageInfo
+
+func (m *GetMemberRequest) GetCid() int32 {
+	if m != nil {
+		return m.Cid
+	}
+	return 0
+}
+
+type SubscribeRequest struct {
+	Filter *Filter `protobuf:"bytes,1,opt,name=filter,proto3" json:"filter,omitempty"`
+	// snapshot sends every matching client before the changes made after it.
+	Snapshot bool `protobuf:"varint,2,opt,name=snapshot,proto3" json:"snapshot,omitempty"`
+	// cursor resumes after the id of the last event received, unless a snapshot is sent.
+	Cursor               uint64   `protobuf:"varint,3,opt,name=cursor,proto3" json:"cursor,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *SubscribeRequest) Reset()         { *m = SubscribeRequest{} }
+func (m *SubscribeRequest) String() string { return proto.CompactTextString(m) }
+func (*SubscribeRequest) ProtoMessage()    {}
+func (*SubscribeRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_d248291538510d87, []int{15}
+}
+
+func (m *SubscribeRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_SubscribeRequest.Unmarshal(m, b)
+}
+func (m *SubscribeRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_SubscribeRequest.Marshal(b, m, deterministic)
+}
+func (m *SubscribeRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_SubscribeRequest.Merge(m, src)
+}
+func (m *SubscribeRequest) XXX_Size() int {
+	return xxx_messageInfo_SubscribeRequest.Size(m)
+}
+func (m *SubscribeRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_SubscribeRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_SubscribeRequest proto.InternalMessageInfo
+
+func (m *SubscribeRequest) GetFilter() *Filter {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *SubscribeRequest) GetSnapshot() bool {
+	if m != nil {
+		return m.Snapshot
+	}
+	return false
+}
+
+func (m *SubscribeRequest) GetCursor() uint64 {
+	if m != nil {
+		return m.Cursor
+	}
+	return 0
+}
+
+func init() {
+	proto.RegisterType((*MemberData)(nil), "dataserver.v1.MemberData")
+	proto.RegisterType((*FlightPlanTime)(nil), "dataserver.v1.FlightPlanTime")
+	proto.RegisterType((*FlightPlan)(nil), "dataserver.v1.FlightPlan")
+	proto.RegisterType((*Pilot)(nil), "dataserver.v1.Pilot")
+	proto.RegisterType((*ATC)(nil), "dataserver.v1.ATC")
+	proto.RegisterType((*Session)(nil), "dataserver.v1.Session")
+	proto.RegisterType((*Anomaly)(nil), "dataserver.v1.Anomaly")
+	proto.RegisterType((*Client)(nil), "dataserver.v1.Client")
+	proto.RegisterType((*ClientList)(nil), "dataserver.v1.ClientList")
+	proto.RegisterType((*Event)(nil), "dataserver.v1.Event")
+	proto.RegisterType((*BoundingBox)(nil), "dataserver.v1.BoundingBox")
+	proto.RegisterType((*Filter)(nil), "dataserver.v1.Filter")
+	proto.RegisterType((*ListClientsRequest)(nil), "dataserver.v1.ListClientsRequest")
+	proto.RegisterType((*GetClientRequest)(nil), "dataserver.v1.GetClientRequest")
+	proto.RegisterType((*GetMemberRequest)(nil), "dataserver.v1.GetMemberRequest")
+	proto.RegisterType((*SubscribeRequest)(nil), "dataserver.v1.SubscribeRequest")
+}
+
+func init() { proto.RegisterFile("dataserver.proto", fileDescriptor_d248291538510d87) }
+
+var fileDescriptor_d248291538510d87 = []byte{
+	// 1241 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x56, 0xcd, 0x6e, 0xdb, 0x46,
+	0x10, 0x36, 0x45, 0x8a, 0x12, 0x87, 0x76, 0x6a, 0x2c, 0x92, 0x80, 0x15, 0x12, 0xc4, 0x61, 0x0a,
+	0x34, 0x87, 0x46, 0x6a, 0xd4, 0x1c, 0x5a, 0x04, 0x3d, 0xc4, 0xce, 0x8f, 0x81, 0xb6, 0x40, 0xb0,
+	0x71, 0x2f, 0xbd, 0x18, 0x2b, 0x69, 0x25, 0xb3, 0xa1, 0xb8, 0xec, 0xee, 0xd2, 0x8d, 0xdf, 0xa0,
+	0x2f, 0xd0, 0xc7, 0x28, 0xd0, 0x27, 0xe8, 0xa5, 0xe7, 0x9e, 0xfb, 0x3a, 0xc5, 0xce, 0x2e, 0x45,
+	0x89, 0x71, 0x95, 0x38, 0xbd, 0xf4, 0xb6, 0x33, 0xfc, 0x76, 0x66, 0xf8, 0x7d, 0x33, 0x43, 0xc2,
+	0xfe, 0x8c, 0x69, 0xa6, 0xb8, 0x3c, 0xe7, 0x72, 0x58, 0x4a, 0xa1, 0x05, 0xd9, 0x5b, 0xf3, 0x9c,
+	0x3f, 0x1c, 0xdc, 0x59, 0x08, 0xb1, 0xc8, 0xf9, 0x08, 0x1f, 0x4e, 0xaa, 0xf9, 0x48, 0x67, 0x4b,
+	0xae, 0x34, 0x5b, 0x96, 0x16, 0x9f, 0x8e, 0x01, 0xbe, 0xe3, 0xcb, 0x09, 0x97, 0x4f, 0x99, 0x66,
+	0x64, 0x1f, 0xfc, 0x69, 0x36, 0x4b, 0xbc, 0x03, 0xef, 0x7e, 0x97, 0x9a, 0x23, 0x21, 0x10, 0x14,
+	0x6c, 0xc9, 0x93, 0xce, 0x81, 0x77, 0x3f, 0xa2, 0x78, 0x4e, 0xff, 0xf0, 0xe0, 0xda, 0xf3, 0x3c,
+	0x5b, 0x9c, 0xe9, 0x97, 0x39, 0x2b, 0x4e, 0xb2, 0x25, 0x27, 0xb7, 0x20, 0x9a, 0xf1, 0x92, 0x49,
+	0x5d, 0x49, 0x8e, 0xd7, 0x23, 0xda, 0x38, 0xc8, 0x3d, 0xd8, 0x3b, 0x13, 0x95, 0x54, 0xa7, 0xbc,
+	0x90, 0xa2, 0xd2, 0x75, 0xb4, 0x5d, 0x74, 0x3e, 0xb3, 0x3e, 0xf2, 0x29, 0x7c, 0xb4, 0xcc, 0x8a,
+	0x4a, 0xf3, 0x06, 0xe6, 0x23, 0xec, 0x9a, 0x73, 0xd7, 0xc0, 0xdb, 0x00, 0x36, 0xda, 0xbc, 0xe2,
+	0x79, 0x12, 0xd8, 0x64, 0xe8, 0x79, 0x5e, 0xf1, 0x9c, 0xdc, 0x85, 0xdd, 0x3a, 0x0e, 0x02, 0xba,
+	0x08, 0x88, 0x9d, 0xcf, 0x40, 0xd2, 0x3f, 0x3b, 0x00, 0xcd, 0x0b, 0x98, 0x1b, 0x73, 0xb4, 0x4e,
+	0x65, 0x95, 0x73, 0xe5, 0xea, 0x8f, 0xad, 0x8f, 0x1a, 0x17, 0x19, 0x40, 0x9f, 0x65, 0x72, 0x2a,
+	0xd9, 0x5c, 0xbb, 0xe2, 0x57, 0xb6, 0xb9, 0x3e, 0x95, 0x55, 0xa6, 0xf8, 0xa9, 0x2a, 0x39, 0x9f,
+	0xb9, 0xaa, 0x63, 0xeb, 0x7b, 0x65, 0x5c, 0x9b, 0xf4, 0x04, 0x6d, 0x7a, 0x12, 0xe8, 0x31, 0x29,
+	0xb3, 0x73, 0x56, 0x17, 0x5b, 0x9b, 0x98, 0x36, 0xd7, 0x99, 0xae, 0x66, 0x3c, 0x09, 0x5d, 0x5a,
+	0x67, 0x9b, 0x98, 0x2c, 0xd7, 0x5c, 0x16, 0x4c, 0xf3, 0xa4, 0x67, 0x63, 0xae, 0x1c, 0xe4, 0x3a,
+	0x74, 0x2d, 0x87, 0x7d, 0x7c, 0x62, 0x0d, 0xf2, 0x10, 0x02, 0xd3, 0x00, 0x49, 0x74, 0xe0, 0xdd,
+	0x8f, 0xc7, 0xb7, 0x87, 0x1b, 0xcd, 0x32, 0xdc, 0xd4, 0x94, 0x22, 0xd4, 0x14, 0x27, 0xf9, 0x92,
+	0xc9, 0xd7, 0x2a, 0x01, 0x5b, 0x9c, 0x33, 0xd3, 0xbf, 0x7d, 0xe8, 0xbe, 0xcc, 0x72, 0xa1, 0xc9,
+	0x4d, 0x08, 0x6d, 0x14, 0x47, 0x9d, 0xb3, 0x4c, 0xf9, 0x53, 0x96, 0xe7, 0x2a, 0x5b, 0x14, 0x35,
+	0x6b, 0xb5, 0x4d, 0x1e, 0x42, 0xb8, 0xc4, 0xc6, 0x43, 0xbe, 0xe2, 0xf1, 0xc7, 0xad, 0x62, 0x9a,
+	0xae, 0xa4, 0x0e, 0x68, 0xd2, 0x48, 0xa6, 0xb3, 0x62, 0x81, 0x14, 0x76, 0xa9, 0xb3, 0x4c, 0x9a,
+	0x9c, 0x39, 0x96, 0x0c, 0x81, 0x1e, 0x5d, 0xd9, 0x86, 0xa5, 0x5c, 0x14, 0x8b, 0x86, 0x42, 0x8f,
+	0x36, 0x8e, 0x0d, 0x7e, 0x7b, 0x18, 0xb3, 0xe1, 0xf7, 0x3a, 0x74, 0xad, 0x9e, 0x7d, 0x7c, 0x60,
+	0x0d, 0x43, 0xc7, 0x19, 0x67, 0x33, 0x53, 0x44, 0x84, 0xfe, 0xda, 0x24, 0x0f, 0x20, 0x28, 0x73,
+	0x56, 0x24, 0x70, 0xe9, 0xeb, 0x34, 0xdc, 0x52, 0x84, 0x91, 0xaf, 0x00, 0x72, 0xb1, 0x10, 0xc5,
+	0x29, 0x0a, 0x12, 0xe3, 0xa5, 0xc1, 0xd0, 0x8e, 0xeb, 0xb0, 0x1e, 0xd7, 0xe1, 0x49, 0x3d, 0xae,
+	0xa6, 0xea, 0x85, 0xb0, 0xc3, 0xf6, 0x35, 0xec, 0xe6, 0x4c, 0xe9, 0xd3, 0xaa, 0x9c, 0x31, 0xcd,
+	0x67, 0xc9, 0xee, 0x3b, 0x2f, 0xc7, 0x06, 0xff, 0xbd, 0x85, 0x93, 0x03, 0x88, 0xb5, 0x64, 0x85,
+	0x2a, 0x45, 0x31, 0xe3, 0x32, 0xd9, 0xc3, 0xd7, 0x58, 0x77, 0xa5, 0xbf, 0xfb, 0xe0, 0x3f, 0x39,
+	0x39, 0xfa, 0x20, 0x5d, 0x09, 0x04, 0xfa, 0xa2, 0xac, 0x67, 0x17, 0xcf, 0x6b, 0x5a, 0x07, 0x57,
+	0xd7, 0xba, 0xbb, 0xa1, 0xf5, 0x2d, 0x88, 0xe6, 0x92, 0xff, 0x54, 0xf1, 0x62, 0x7a, 0x81, 0x7a,
+	0x76, 0x69, 0xe3, 0x30, 0x85, 0xcd, 0xd9, 0x34, 0xcb, 0x33, 0x7d, 0x51, 0xeb, 0x59, 0xdb, 0x38,
+	0x11, 0xac, 0x58, 0xf0, 0x5a, 0x4f, 0x34, 0x36, 0x7a, 0x27, 0xda, 0xd6, 0x3b, 0xd0, 0xee, 0x1d,
+	0x02, 0x01, 0xd3, 0x99, 0x42, 0xe9, 0x22, 0x8a, 0xe7, 0x96, 0xa8, 0xbb, 0xff, 0x45, 0xd4, 0xbd,
+	0x2b, 0x89, 0x9a, 0xfe, 0xe5, 0x43, 0xef, 0x15, 0x57, 0x2a, 0x13, 0xc5, 0xff, 0x51, 0xb6, 0x75,
+	0x9a, 0xc3, 0x6d, 0x34, 0xf7, 0xb6, 0x8d, 0x68, 0xbf, 0x35, 0xa2, 0xf5, 0xc8, 0x45, 0x1f, 0x32,
+	0x72, 0x70, 0x15, 0x75, 0x1e, 0x43, 0x6c, 0x8c, 0xf9, 0xfc, 0x7d, 0xc7, 0x15, 0x2c, 0x1c, 0x2f,
+	0x0f, 0xa0, 0x3f, 0xab, 0x0c, 0x11, 0xa2, 0xc0, 0x9e, 0xf0, 0xe9, 0xca, 0x46, 0xc2, 0x38, 0x53,
+	0xa2, 0x40, 0xc1, 0x23, 0xea, 0xac, 0x54, 0x40, 0xef, 0x49, 0x21, 0x96, 0x2c, 0xbf, 0xb8, 0xe4,
+	0xa3, 0xbc, 0x4d, 0xc8, 0x5b, 0x10, 0xd5, 0x67, 0x95, 0xf8, 0x07, 0xbe, 0xf9, 0x2c, 0xac, 0x1c,
+	0x66, 0x7d, 0x59, 0x82, 0x54, 0x12, 0xe0, 0xb3, 0xda, 0x4c, 0xcf, 0x21, 0x3c, 0xca, 0x33, 0x5e,
+	0x68, 0xf2, 0x19, 0x74, 0x4b, 0xb3, 0xd6, 0x31, 0x63, 0x3c, 0xbe, 0xde, 0xa2, 0x15, 0x57, 0xfe,
+	0xf1, 0x0e, 0xb5, 0x20, 0xf2, 0x08, 0x60, 0x2a, 0x0a, 0x2d, 0x45, 0x9e, 0x73, 0x89, 0xd5, 0xc4,
+	0x63, 0xd2, 0xba, 0xf2, 0xe4, 0xe4, 0xe8, 0x78, 0x87, 0xae, 0xe1, 0x0e, 0xfb, 0x10, 0x4e, 0x31,
+	0x5b, 0xfa, 0x8b, 0x07, 0x60, 0x13, 0x7f, 0x9b, 0x29, 0x93, 0x3c, 0xc4, 0xb8, 0xe6, 0x2b, 0xec,
+	0xff, 0x5b, 0x76, 0xea, 0x30, 0xe4, 0x11, 0xc4, 0x4d, 0x50, 0x95, 0x74, 0x0e, 0xfc, 0xcb, 0xb3,
+	0xd3, 0x75, 0x98, 0x9b, 0x8f, 0x8c, 0xe5, 0xd8, 0xed, 0x01, 0x75, 0x56, 0xfa, 0xab, 0x0f, 0xdd,
+	0x67, 0xe7, 0x86, 0x82, 0x6b, 0xd0, 0x71, 0x8c, 0x07, 0xb4, 0x93, 0xcd, 0xf0, 0x9f, 0x82, 0x2b,
+	0xc5, 0x16, 0xfc, 0x14, 0xa7, 0xa4, 0xe3, 0xfe, 0x29, 0xac, 0xef, 0xc4, 0x0c, 0xcb, 0xba, 0x26,
+	0x7e, 0x4b, 0x93, 0x2f, 0x21, 0x5a, 0xfd, 0x77, 0x25, 0xc1, 0x3b, 0x7b, 0xa7, 0x01, 0x37, 0x5a,
+	0x74, 0xaf, 0xae, 0x45, 0xf8, 0x7e, 0x5a, 0x90, 0x91, 0x9b, 0xa2, 0xde, 0x3b, 0xa6, 0xe8, 0x78,
+	0xc7, 0xcd, 0xd1, 0xd8, 0x34, 0x11, 0xae, 0x1a, 0x9c, 0xc8, 0x78, 0x7c, 0xb3, 0x75, 0xc7, 0x2d,
+	0xa2, 0xe3, 0x1d, 0x5a, 0x03, 0xcd, 0x1d, 0x66, 0xfb, 0x39, 0x89, 0x2e, 0xbd, 0xe3, 0xba, 0xdd,
+	0xdc, 0x71, 0xc0, 0xc3, 0x10, 0x02, 0x83, 0x49, 0x19, 0xc4, 0x87, 0xa2, 0x2a, 0xcc, 0x57, 0xf6,
+	0x50, 0xbc, 0xc1, 0x0f, 0xb3, 0xa8, 0xf4, 0x19, 0xea, 0xe3, 0x51, 0x6b, 0x98, 0x05, 0xf6, 0x33,
+	0x57, 0xf6, 0xef, 0xcc, 0xa3, 0x78, 0x36, 0xc8, 0x42, 0x48, 0x7d, 0x86, 0x82, 0x78, 0xd4, 0x1a,
+	0x06, 0xc9, 0x99, 0xd2, 0x28, 0x84, 0x47, 0xf1, 0x9c, 0xfe, 0x08, 0xe1, 0xf3, 0x2c, 0xd7, 0xad,
+	0x25, 0xe9, 0xb5, 0x74, 0x1c, 0x42, 0x30, 0x99, 0x88, 0x37, 0xae, 0xcb, 0x07, 0xad, 0x37, 0x58,
+	0xab, 0x91, 0x22, 0xce, 0xe4, 0x37, 0xed, 0x52, 0xcf, 0xa1, 0x35, 0xd2, 0x23, 0x20, 0xa6, 0xd5,
+	0x6d, 0xd3, 0x2b, 0x6a, 0xbe, 0x5d, 0x4a, 0x93, 0x07, 0x10, 0xce, 0xb1, 0x02, 0x37, 0x76, 0x37,
+	0xda, 0x3a, 0xe0, 0x43, 0xea, 0x40, 0xe9, 0x10, 0xf6, 0x5f, 0x70, 0x17, 0xa3, 0x0e, 0xb1, 0xa5,
+	0xf4, 0xf4, 0x13, 0xc4, 0xdb, 0x8d, 0x5d, 0xe3, 0xdf, 0x5a, 0x2c, 0x69, 0x05, 0xfb, 0xaf, 0xaa,
+	0x89, 0x9a, 0xca, 0x6c, 0xc2, 0x3f, 0xac, 0x30, 0x53, 0x84, 0x2a, 0x58, 0xa9, 0xce, 0x84, 0xd5,
+	0xa2, 0x4f, 0x57, 0xb6, 0x19, 0xbc, 0x69, 0x25, 0x95, 0x90, 0xf5, 0xe0, 0x59, 0x6b, 0xfc, 0x5b,
+	0x07, 0xe0, 0xe9, 0x2a, 0x28, 0xf9, 0x06, 0xe2, 0x35, 0x82, 0xc8, 0xdd, 0x56, 0xc2, 0xb7, 0xc9,
+	0x1b, 0xb4, 0x9b, 0x76, 0x6d, 0xa1, 0x1c, 0x41, 0xb4, 0x22, 0x8a, 0xdc, 0x69, 0xe1, 0xda, 0x14,
+	0x0e, 0x6e, 0x5c, 0x1a, 0x88, 0xbc, 0x80, 0x68, 0xc5, 0xde, 0x65, 0x41, 0x36, 0x78, 0xdd, 0x56,
+	0xcd, 0x53, 0x88, 0x56, 0x04, 0xbf, 0x15, 0xa8, 0x4d, 0xfd, 0xa0, 0x3d, 0xee, 0xb8, 0x9c, 0x3e,
+	0xf7, 0x0e, 0xef, 0xfd, 0x70, 0xb7, 0x79, 0x30, 0xca, 0x0a, 0xfc, 0xe9, 0xcf, 0x47, 0xe5, 0xeb,
+	0xc5, 0x48, 0x96, 0xd3, 0x51, 0x39, 0x79, 0x5c, 0x4e, 0x26, 0x21, 0x6e, 0x96, 0x2f, 0xfe, 0x19,
+	0x00, 0x38, 0x20, 0xd7, 0x6f, 0x24, 0x0e, 0x00, 0x00,
+}
+
+// Reference imports to suppress errors if they are not otherwise used.
+var _ context.Context
+var _ grpc.ClientConn
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+const _ = grpc.SupportPackageIsVersion4
+
+// DataserverClient is the client API for Dataserver service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
+type DataserverClient interface {
+	// ListClients returns every client matching the filter.
+	ListClients(ctx context.Context, in *ListClientsRequest, opts ...grpc.CallOption) (*ClientList, error)
+	// GetClient looks up a single client by callsign.
+	GetClient(ctx context.Context, in *GetClientRequest, opts ...grpc.CallOption) (*Client, error)
+	// GetMember returns every client connected with a CID.
+	GetMember(ctx context.Context, in *GetMemberRequest, opts ...grpc.CallOption) (*ClientList, error)
+	// Subscribe streams changes, optionally starting with a snapshot of the client list.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Dataserver_SubscribeClient, error)
+}
+
+type dataserverClient struct {
+	cc *grpc.ClientConn
+}
+
+func NewDataserverClient(cc *grpc.ClientConn) DataserverClient {
+	return &dataserverClient{cc}
+}
+
+func (c *dataserverClient) ListClients(ctx context.Context, in *ListClientsRequest, opts ...grpc.CallOption) (*ClientList, error) {
+	out := new(ClientList)
+	err := c.cc.Invoke(ctx, "/dataserver.v1.Dataserver/ListClients", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataserverClient) GetClient(ctx context.Context, in *GetClientRequest, opts ...grpc.CallOption) (*Client, error) {
+	out := new(Client)
+	err := c.cc.Invoke(ctx, "/dataserver.v1.Dataserver/GetClient", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataserverClient) GetMember(ctx context.Context, in *GetMemberRequest, opts ...grpc.CallOption) (*ClientList, error) {
+	out := new(ClientList)
+	err := c.cc.Invoke(ctx, "/dataserver.v1.Dataserver/GetMember", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *dataserverClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (Dataserver_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &_Dataserver_serviceDesc.Streams[0], "/dataserver.v1.Dataserver/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &dataserverSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Dataserver_SubscribeClient interface {
+	Recv() (*Event, error)
+	grpc.ClientStream
+}
+
+type dataserverSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *dataserverSubscribeClient) Recv() (*Event, error) {
+	m := new(Event)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// DataserverServer is the server API for Dataserver service.
+type DataserverServer interface {
+	// ListClients returns every client matching the filter.
+	ListClients(context.Context, *ListClientsRequest) (*ClientList, error)
+	// GetClient looks up a single client by callsign.
+	GetClient(context.Context, *GetClientRequest) (*Client, error)
+	// GetMember returns every client connected with a CID.
+	GetMember(context.Context, *GetMemberRequest) (*ClientList, error)
+	// Subscribe streams changes, optionally starting with a snapshot of the client list.
+	Subscribe(*SubscribeRequest, Dataserver_SubscribeServer) error
+}
+
+// UnimplementedDataserverServer can be embedded to have forward compatible implementations.
+type UnimplementedDataserverServer struct {
+}
+
+func (*UnimplementedDataserverServer) ListClients(ctx context.Context, req *ListClientsRequest) (*ClientList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListClients not implemented")
+}
+func (*UnimplementedDataserverServer) GetClient(ctx context.Context, req *GetClientRequest) (*Client, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClient not implemented")
+}
+func (*UnimplementedDataserverServer) GetMember(ctx context.Context, req *GetMemberRequest) (*ClientList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMember not implemented")
+}
+func (*UnimplementedDataserverServer) Subscribe(req *SubscribeRequest, srv Dataserver_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+
+func RegisterDataserverServer(s *grpc.Server, srv DataserverServer) {
+	s.RegisterService(&_Dataserver_serviceDesc, srv)
+}
+
+func _Dataserver_ListClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListClientsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataserverServer).ListClients(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dataserver.v1.Dataserver/ListClients",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataserverServer).ListClients(ctx, req.(*ListClientsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dataserver_GetClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetClientRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataserverServer).GetClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dataserver.v1.Dataserver/GetClient",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataserverServer).GetClient(ctx, req.(*GetClientRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dataserver_GetMember_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMemberRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(DataserverServer).GetMember(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/dataserver.v1.Dataserver/GetMember",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(DataserverServer).GetMember(ctx, req.(*GetMemberRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Dataserver_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(DataserverServer).Subscribe(m, &dataserverSubscribeServer{stream})
+}
+
+type Dataserver_SubscribeServer interface {
+	Send(*Event) error
+	grpc.ServerStream
+}
+
+type dataserverSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *dataserverSubscribeServer) Send(m *Event) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+var _Dataserver_serviceDesc = grpc.ServiceDesc{
+	ServiceName: "dataserver.v1.Dataserver",
+	HandlerType: (*DataserverServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListClients",
+			Handler:    _Dataserver_ListClients_Handler,
+		},
+		{
+			MethodName: "GetClient",
+			Handler:    _Dataserver_GetClient_Handler,
+		},
+		{
+			MethodName: "GetMember",
+			Handler:    _Dataserver_GetMember_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _Dataserver_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "dataserver.proto",
+}
@@ -0,0 +1,201 @@
+package rpc
+
+//go:generate protoc -I ../../../api --go_out=plugins=grpc,paths=source_relative:pb dataserver.proto
+
+import (
+	"context"
+	"dataserver/internal/pkg/dataserver"
+	"dataserver/internal/pkg/geo"
+	"dataserver/internal/pkg/rpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"strings"
+	"time"
+)
+
+// Server implements the Dataserver gRPC service over the live client list.
+type Server struct {
+	Context     *dataserver.Context
+	ClientTypes dataserver.ClientTypes
+}
+
+// Message types sent to subscribers which asked for a snapshot.
+const (
+	snapshotMessageType         = "snapshot"
+	snapshotCompleteMessageType = "snapshot_complete"
+)
+
+// Register adds the Dataserver service to a gRPC server.
+func (s *Server) Register(server *grpc.Server) {
+	pb.RegisterDataserverServer(server, s)
+}
+
+// ListClients returns every client matching the filter.
+func (s *Server) ListClients(ctx context.Context, req *pb.ListClientsRequest) (*pb.ClientList, error) {
+	filter, err := s.filter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+	return toClientList(filter.FilterClientList(s.clientList())), nil
+}
+
+// GetClient looks up a single client by callsign.
+func (s *Server) GetClient(ctx context.Context, req *pb.GetClientRequest) (*pb.Client, error) {
+	clientList := s.clientList()
+	for _, v := range clientList.PilotData {
+		if strings.EqualFold(v.Callsign, req.Callsign) {
+			return &pb.Client{Client: &pb.Client_Pilot{Pilot: toPilot(v)}}, nil
+		}
+	}
+	for _, v := range clientList.ATCData {
+		if strings.EqualFold(v.Callsign, req.Callsign) {
+			return &pb.Client{Client: &pb.Client_Controller{Controller: toATC(v)}}, nil
+		}
+	}
+	return nil, status.Error(codes.NotFound, "Client not found.")
+}
+
+// GetMember returns every client connected with a CID.
+func (s *Server) GetMember(ctx context.Context, req *pb.GetMemberRequest) (*pb.ClientList, error) {
+	member := dataserver.ClientList{}
+	if req.Cid != 0 {
+		clientList := s.clientList()
+		for _, v := range clientList.PilotData {
+			if v.Member.CID == int(req.Cid) {
+				member.PilotData = append(member.PilotData, v)
+			}
+		}
+		for _, v := range clientList.ATCData {
+			if v.Member.CID == int(req.Cid) {
+				member.ATCData = append(member.ATCData, v)
+			}
+		}
+	}
+	if len(member.PilotData) == 0 && len(member.ATCData) == 0 {
+		return nil, status.Error(codes.NotFound, "Member not connected.")
+	}
+	return toClientList(member), nil
+}
+
+// Subscribe streams changes, optionally starting with a snapshot of the client list.
+func (s *Server) Subscribe(req *pb.SubscribeRequest, stream pb.Dataserver_SubscribeServer) error {
+	filter, err := s.filter(req.Filter)
+	if err != nil {
+		return err
+	}
+	subscribe := s.Context.Events.Subscribe
+	cursor := req.Cursor
+	if req.Snapshot {
+		// Subscribe only once the snapshot is sent, resuming from the last change it includes,
+		// so a large snapshot doesn't leave the subscriber too far behind
+		var clientList dataserver.ClientList
+		clientList, cursor = s.Context.SnapshotAt()
+		err = s.sendSnapshot(filter.FilterClientList(s.redact(clientList)), stream)
+		if err != nil {
+			return err
+		}
+		subscribe = s.Context.Events.SubscribeAfter
+	}
+	backlog, events, ok := subscribe(cursor)
+	if !ok {
+		return status.Error(codes.OutOfRange, "Cursor is no longer available.")
+	}
+	defer s.Context.Events.Unsubscribe(events)
+
+	for _, v := range backlog {
+		if filter.Matches(v) {
+			if err := stream.Send(toEvent(v)); err != nil {
+				return err
+			}
+		}
+	}
+	for {
+		select {
+		case event, open := <-events:
+			if !open {
+				return status.Error(codes.ResourceExhausted, "Subscriber fell too far behind.")
+			}
+			if filter.Matches(event) {
+				if err := stream.Send(toEvent(event)); err != nil {
+					return err
+				}
+			}
+		case <-stream.Context().Done():
+			return nil
+		}
+	}
+}
+
+// sendSnapshot sends every client followed by a marker that the snapshot is complete
+func (s *Server) sendSnapshot(clientList dataserver.ClientList, stream pb.Dataserver_SubscribeServer) error {
+	now := toTimestamp(time.Now().UTC())
+	for _, v := range clientList.PilotData {
+		err := stream.Send(&pb.Event{
+			MessageType: snapshotMessageType,
+			Callsign:    v.Callsign,
+			Timestamp:   now,
+			Data:        &pb.Event_Pilot{Pilot: toPilot(v)},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for _, v := range clientList.ATCData {
+		err := stream.Send(&pb.Event{
+			MessageType: snapshotMessageType,
+			Callsign:    v.Callsign,
+			Timestamp:   now,
+			Data:        &pb.Event_Controller{Controller: toATC(v)},
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return stream.Send(&pb.Event{
+		MessageType: snapshotCompleteMessageType,
+		Timestamp:   now,
+	})
+}
+
+// clientList takes a redacted snapshot of the clients this service exposes
+func (s *Server) clientList() dataserver.ClientList {
+	return s.redact(s.Context.ClientList.Snapshot())
+}
+
+// redact keeps the clients this service exposes, without the members who opted out
+func (s *Server) redact(clientList dataserver.ClientList) dataserver.ClientList {
+	return s.Context.OptOut.RedactClientList(clientList.FilterClientTypes(s.ClientTypes), "grpc")
+}
+
+// filter converts a request filter, limiting client types to those this service exposes
+func (s *Server) filter(f *pb.Filter) (dataserver.EventFilter, error) {
+	if f == nil {
+		return dataserver.EventFilter{ClientTypes: s.ClientTypes}, nil
+	}
+	clientTypes := s.ClientTypes
+	if len(f.Types) > 0 {
+		clientTypes = dataserver.ClientTypes{}
+		for _, v := range f.Types {
+			clientType := dataserver.ClientType(v)
+			if !dataserver.AllClientTypes[clientType] {
+				return dataserver.EventFilter{}, status.Errorf(codes.InvalidArgument, "Unknown client type %s.", v)
+			}
+			clientTypes[clientType] = s.ClientTypes.Includes(clientType)
+		}
+	}
+	var bbox *geo.BoundingBox
+	if f.Bbox != nil {
+		bbox = &geo.BoundingBox{
+			South: f.Bbox.South,
+			West:  f.Bbox.West,
+			North: f.Bbox.North,
+			East:  f.Bbox.East,
+		}
+	}
+	filter, err := dataserver.NewEventFilter(f.Callsign, bbox, clientTypes)
+	if err != nil {
+		return dataserver.EventFilter{}, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return filter, nil
+}
@@ -0,0 +1,168 @@
+package rpc
+
+import (
+	"context"
+	"dataserver/internal/pkg/dataserver"
+	"dataserver/internal/pkg/geo"
+	"dataserver/internal/pkg/rpc/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sync"
+	"testing"
+)
+
+// fakeStream collects the events sent to a subscriber
+type fakeStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	sent   []*pb.Event
+	onSend func(*pb.Event)
+}
+
+func (f *fakeStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeStream) Send(event *pb.Event) error {
+	f.sent = append(f.sent, event)
+	if f.onSend != nil {
+		f.onSend(event)
+	}
+	return nil
+}
+
+// newTestServer serves a pilot, a controller and an observer hidden from the service
+func newTestServer(history int) *Server {
+	return &Server{
+		Context: &dataserver.Context{
+			ClientList: &dataserver.ClientList{
+				PilotData: []dataserver.Pilot{
+					{Callsign: "BAW2", Member: dataserver.MemberData{CID: 2, Name: "Two"}},
+				},
+				ATCData: []dataserver.ATC{
+					{Callsign: "EGLL_TWR", ClientType: dataserver.ClientTypeController},
+					{Callsign: "EGLL_OBS", ClientType: dataserver.ClientTypeObserver},
+				},
+				Mutex: &sync.RWMutex{},
+			},
+			OptOut: &dataserver.OptOutList{CIDs: map[int]bool{2: true}, Mutex: &sync.RWMutex{}},
+			Events: dataserver.NewEventBus(history),
+			Index:  geo.NewIndex(1),
+			Mutex:  &sync.RWMutex{},
+		},
+		ClientTypes: dataserver.ClientTypesOf([]string{"pilot", "controller"}),
+	}
+}
+
+// publishPilots publishes position updates for n pilots
+func publishPilots(bus *dataserver.EventBus, n int) {
+	for i := 0; i < n; i++ {
+		bus.Publish(dataserver.Event{MessageType: "update_position", Callsign: "BAW2", Data: dataserver.Pilot{Callsign: "BAW2"}})
+	}
+}
+
+func TestFilter(t *testing.T) {
+	s := newTestServer(10)
+	tests := []struct {
+		name    string
+		filter  *pb.Filter
+		want    []string
+		wantErr codes.Code
+	}{
+		{"None", nil, []string{"BAW2", "EGLL_TWR"}, codes.OK},
+		{"Callsign", &pb.Filter{Callsign: "egll_*"}, []string{"EGLL_TWR"}, codes.OK},
+		{"Types", &pb.Filter{Types: []string{"pilot"}}, []string{"BAW2"}, codes.OK},
+		{"Hidden type", &pb.Filter{Types: []string{"observer"}}, nil, codes.OK},
+		{"Unknown type", &pb.Filter{Types: []string{"tower"}}, nil, codes.InvalidArgument},
+		{"Invalid callsign", &pb.Filter{Callsign: "["}, nil, codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clientList, err := s.ListClients(context.Background(), &pb.ListClientsRequest{Filter: tt.filter})
+			if status.Code(err) != tt.wantErr {
+				t.Fatalf("ListClients() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, v := range clientList.Pilots {
+				got = append(got, v.Callsign)
+				if v.Member.Cid != 0 {
+					t.Errorf("ListClients() sent opted out member %v", v.Member)
+				}
+			}
+			for _, v := range clientList.Controllers {
+				got = append(got, v.Callsign)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("ListClients() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("ListClients() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     *pb.SubscribeRequest
+		before  int
+		during  int
+		want    []string
+		wantErr codes.Code
+	}{
+		{"Resume", &pb.SubscribeRequest{Cursor: 2}, 4, 0, []string{"update_position", "update_position"}, codes.OK},
+		{"Expired cursor", &pb.SubscribeRequest{Cursor: 1}, 20, 0, nil, codes.OutOfRange},
+		{"Snapshot", &pb.SubscribeRequest{Snapshot: true}, 3, 0, []string{"snapshot", "snapshot", "snapshot_complete"}, codes.OK},
+		{"Snapshot filtered", &pb.SubscribeRequest{Snapshot: true, Filter: &pb.Filter{Types: []string{"pilot"}}}, 0, 0, []string{"snapshot", "snapshot_complete"}, codes.OK},
+		{"Snapshot while busy", &pb.SubscribeRequest{Snapshot: true}, 0, 300, []string{"snapshot", "snapshot", "snapshot_complete"}, codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(1000)
+			if tt.wantErr == codes.OutOfRange {
+				s = newTestServer(10)
+			}
+			publishPilots(s.Context.Events, tt.before)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			want := len(tt.want) + tt.during
+			stream := &fakeStream{ctx: ctx}
+			stream.onSend = func(event *pb.Event) {
+				// More changes arrive while the snapshot is sent than a subscriber can buffer
+				if len(stream.sent) == 1 {
+					publishPilots(s.Context.Events, tt.during)
+				}
+				if len(stream.sent) == want {
+					cancel()
+				}
+			}
+			err := s.Subscribe(tt.req, stream)
+			if status.Code(err) != tt.wantErr {
+				t.Fatalf("Subscribe() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(stream.sent) != want {
+				t.Fatalf("Subscribe() sent %d events, want %d", len(stream.sent), want)
+			}
+			for i, v := range tt.want {
+				if stream.sent[i].MessageType != v {
+					t.Errorf("Subscribe() event %d = %v, want %v", i, stream.sent[i].MessageType, v)
+				}
+			}
+			for i, v := range stream.sent[len(tt.want):] {
+				if v.MessageType != "update_position" || v.Id != uint64(tt.before+i+1) {
+					t.Errorf("Subscribe() change %d = %v %d, want update_position %d", i, v.MessageType, v.Id, tt.before+i+1)
+				}
+			}
+		})
+	}
+}
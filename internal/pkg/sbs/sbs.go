@@ -0,0 +1,101 @@
+package sbs
+
+import (
+	"bufio"
+	"dataserver/internal/pkg/dataserver"
+	"fmt"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"net"
+	"strings"
+	"time"
+)
+
+// writeTimeout is how long a slow reader can hold up a write before it is disconnected.
+const writeTimeout = 10 * time.Second
+
+// Server streams pilot positions as SBS-1 BaseStation messages to every connected reader.
+type Server struct {
+	Context *dataserver.Context
+}
+
+// Serve accepts readers until the listener is closed.
+func (s *Server) Serve(listener net.Listener) error {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		go s.handle(conn)
+	}
+}
+
+// handle sends the current positioned pilots to a reader and then each position as it arrives
+func (s *Server) handle(conn net.Conn) {
+	defer conn.Close()
+	log.WithField("address", conn.RemoteAddr().String()).Debug("SBS reader connected.")
+	_, events, _ := s.Context.Events.Subscribe(0)
+	defer s.Context.Events.Unsubscribe(events)
+
+	writer := bufio.NewWriter(conn)
+	clientList := s.Context.ClientList.Snapshot()
+	clientList = s.Context.OptOut.RedactClientList(clientList, "sbs")
+	for _, v := range clientList.PilotData {
+		if v.LastUpdated.IsZero() {
+			continue
+		}
+		if !s.write(conn, writer, v) {
+			return
+		}
+	}
+	for event := range events {
+		pilot, ok := event.Data.(dataserver.Pilot)
+		if !ok || event.MessageType != "update_position" {
+			continue
+		}
+		if !s.write(conn, writer, pilot) {
+			return
+		}
+	}
+}
+
+// write sends the messages of a single pilot, reporting whether the reader is still connected
+func (s *Server) write(conn net.Conn, writer *bufio.Writer, pilot dataserver.Pilot) bool {
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	for _, v := range Messages(pilot, time.Now().UTC()) {
+		writer.WriteString(v)
+		writer.WriteString("\r\n")
+	}
+	err := writer.Flush()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"address": conn.RemoteAddr().String(),
+			"error":   err,
+		}).Debug("SBS reader disconnected.")
+		return false
+	}
+	return true
+}
+
+// Messages formats a pilot as identification, airborne position and velocity messages.
+func Messages(pilot dataserver.Pilot, now time.Time) []string {
+	icao := strings.ToUpper(dataserver.ICAOAddress(pilot.Callsign))
+	return []string{
+		message(1, icao, now, pilot.Callsign, "", "", "", "", "", ""),
+		message(3, icao, now, "", fmt.Sprint(pilot.Altitude), "", "", fmt.Sprintf("%.5f", pilot.Latitude), fmt.Sprintf("%.5f", pilot.Longitude), ""),
+		message(4, icao, now, "", "", fmt.Sprint(pilot.Speed), fmt.Sprint(pilot.Heading), "", "", ""),
+		message(6, icao, now, "", "", "", "", "", "", dataserver.Squawk(pilot.Transponder)),
+	}
+}
+
+// message formats a single BaseStation MSG line, leaving the alert, emergency, ident and ground flags unset
+func message(transmissionType int, icao string, now time.Time, callsign string, altitude string, speed string, track string, latitude string, longitude string, squawk string) string {
+	date := now.Format("2006/01/02")
+	clock := now.Format("15:04:05.000")
+	return strings.Join([]string{
+		"MSG", fmt.Sprint(transmissionType), "1", "1", icao, "1",
+		date, clock, date, clock,
+		callsign, altitude, speed, track, latitude, longitude, "", squawk,
+		"", "", "", "",
+	}, ",")
+}
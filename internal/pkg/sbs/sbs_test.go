@@ -0,0 +1,84 @@
+package sbs
+
+import (
+	"bufio"
+	"dataserver/internal/pkg/dataserver"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMessages(t *testing.T) {
+	now := time.Date(2019, 11, 2, 14, 5, 9, 123000000, time.UTC)
+	pilot := dataserver.Pilot{
+		Callsign:    "AAL1",
+		Latitude:    51.4775,
+		Longitude:   -0.4614,
+		Altitude:    35000,
+		Speed:       450,
+		Heading:     270,
+		Transponder: 1200,
+	}
+	icao := strings.ToUpper(dataserver.ICAOAddress("AAL1"))
+	want := []string{
+		"MSG,1,1,1," + icao + ",1,2019/11/02,14:05:09.123,2019/11/02,14:05:09.123,AAL1,,,,,,,,,,,",
+		"MSG,3,1,1," + icao + ",1,2019/11/02,14:05:09.123,2019/11/02,14:05:09.123,,35000,,,51.47750,-0.46140,,,,,,",
+		"MSG,4,1,1," + icao + ",1,2019/11/02,14:05:09.123,2019/11/02,14:05:09.123,,,450,270,,,,,,,,",
+		"MSG,6,1,1," + icao + ",1,2019/11/02,14:05:09.123,2019/11/02,14:05:09.123,,,,,,,,1200,,,,",
+	}
+	got := Messages(pilot, now)
+	if len(got) != len(want) {
+		t.Fatalf("Messages() len = %d, want %d", len(got), len(want))
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Errorf("Messages()[%d] = %s, want %s", i, got[i], want[i])
+		}
+		if fields := strings.Split(got[i], ","); len(fields) != 22 {
+			t.Errorf("Messages()[%d] has %d fields, want 22", i, len(fields))
+		}
+	}
+	if dataserver.ICAOAddress("AAL1") != dataserver.ICAOAddress("AAL1") || len(icao) != 6 {
+		t.Errorf("ICAOAddress() = %s, want a stable 6 digit address", icao)
+	}
+}
+
+func TestHandle(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := &Server{Context: &dataserver.Context{
+		ClientList: &dataserver.ClientList{
+			PilotData: []dataserver.Pilot{
+				{Callsign: "AAL1", Latitude: 40.64, Longitude: -73.78, LastUpdated: now},
+				{Callsign: "BAW2"},
+			},
+			Mutex: &sync.RWMutex{},
+		},
+		OptOut: &dataserver.OptOutList{CIDs: map[int]bool{}, Mutex: &sync.RWMutex{}},
+		Events: dataserver.NewEventBus(10),
+	}}
+	server, client := net.Pipe()
+	defer client.Close()
+	go s.handle(server)
+	client.SetReadDeadline(time.Now().Add(5 * time.Second))
+	scanner := bufio.NewScanner(client)
+	callsigns := func(n int) []string {
+		var got []string
+		for i := 0; i < n && scanner.Scan(); i++ {
+			if fields := strings.Split(scanner.Text(), ","); fields[1] == "1" {
+				got = append(got, fields[10])
+			}
+		}
+		return got
+	}
+
+	if got := callsigns(4); len(got) != 1 || got[0] != "AAL1" {
+		t.Errorf("handle() sent %v, want only the positioned pilot", got)
+	}
+	s.Context.Events.Publish(dataserver.Event{MessageType: "add_client", Callsign: "DAL3", Data: dataserver.Pilot{Callsign: "DAL3"}})
+	s.Context.Events.Publish(dataserver.Event{MessageType: "update_position", Callsign: "UAL4", Data: dataserver.Pilot{Callsign: "UAL4", LastUpdated: now}})
+	if got := callsigns(4); len(got) != 1 || got[0] != "UAL4" {
+		t.Errorf("handle() sent %v, want only the position update", got)
+	}
+}